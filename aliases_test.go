@@ -0,0 +1,140 @@
+package gonfiguration_test
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/psyb0t/gonfiguration"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAliases(t *testing.T) {
+	type Config struct {
+		DatabaseURL string `env:"DATABASE_URL,alias=DB_URL,alias=POSTGRES_URL"`
+	}
+
+	testCases := []struct {
+		name     string
+		env      map[string]string
+		expected string
+		errorIs  error
+	}{
+		{
+			name:     "primary key",
+			env:      map[string]string{"DATABASE_URL": "postgres://new"},
+			expected: "postgres://new",
+		},
+		{
+			name:     "first alias",
+			env:      map[string]string{"DB_URL": "postgres://old"},
+			expected: "postgres://old",
+		},
+		{
+			name:     "second alias",
+			env:      map[string]string{"POSTGRES_URL": "postgres://older"},
+			expected: "postgres://older",
+		},
+		{
+			name: "same value under several names",
+			env: map[string]string{
+				"DATABASE_URL": "postgres://same",
+				"POSTGRES_URL": "postgres://same",
+			},
+			expected: "postgres://same",
+		},
+		{
+			name: "conflicting values",
+			env: map[string]string{
+				"DB_URL":       "postgres://a",
+				"POSTGRES_URL": "postgres://b",
+			},
+			errorIs: gonfiguration.ErrConflictingAliases,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			defer gonfiguration.Reset()
+
+			for key, val := range tc.env {
+				t.Setenv(key, val)
+			}
+
+			cfg := Config{}
+			err := gonfiguration.Parse(&cfg)
+
+			if tc.errorIs != nil {
+				require.ErrorIs(t, err, tc.errorIs)
+				require.Contains(t, err.Error(), "field DATABASE_URL")
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, cfg.DatabaseURL)
+		})
+	}
+
+	t.Run("required satisfied by alias", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		type RequiredConfig struct {
+			DatabaseURL string `env:"DATABASE_URL,required,alias=DB_URL"`
+		}
+
+		t.Setenv("DB_URL", "postgres://old")
+
+		cfg := RequiredConfig{}
+		require.NoError(t, gonfiguration.Parse(&cfg))
+		require.Equal(t, "postgres://old", cfg.DatabaseURL)
+	})
+
+	t.Run("aliases get the prefix", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		t.Setenv("APP1_DB_URL", "postgres://app1")
+
+		cfg := Config{}
+		require.NoError(t, gonfiguration.Parse(&cfg, gonfiguration.WithPrefix("APP1_")))
+		require.Equal(t, "postgres://app1", cfg.DatabaseURL)
+	})
+}
+
+func TestParseDeprecatedAlias(t *testing.T) {
+	type Config struct {
+		DatabaseURL string `env:"DATABASE_URL,alias=DB_URL,deprecated"`
+	}
+
+	parse := func(t *testing.T) string {
+		t.Helper()
+
+		var logs bytes.Buffer
+
+		logger := slog.New(slog.NewTextHandler(&logs, nil))
+
+		cfg := Config{}
+		require.NoError(t, gonfiguration.Parse(&cfg, gonfiguration.WithLogger(logger)))
+
+		return logs.String()
+	}
+
+	t.Run("warns when the old name is used", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		t.Setenv("DB_URL", "postgres://old")
+
+		logs := parse(t)
+		require.Contains(t, logs, "level=WARN")
+		require.Contains(t, logs, "key=DB_URL")
+		require.Contains(t, logs, "replacement=DATABASE_URL")
+	})
+
+	t.Run("quiet with the new name", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		t.Setenv("DATABASE_URL", "postgres://new")
+
+		require.Empty(t, parse(t))
+	})
+}
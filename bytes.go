@@ -0,0 +1,102 @@
+package gonfiguration
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"reflect"
+	"strings"
+
+	"github.com/psyb0t/ctxerrors"
+)
+
+// Values for the `encoding` tag on []byte and [N]byte fields.
+const (
+	encodingRaw       = "raw"
+	encodingBase64    = "base64"
+	encodingBase64URL = "base64url"
+	encodingHex       = "hex"
+)
+
+func setBytes(
+	fieldValue reflect.Value,
+	envVal string,
+	format valueFormat,
+) error {
+	b, err := decodeBytes(envVal, format.encoding)
+	if err != nil {
+		return err
+	}
+
+	if fieldValue.Kind() == reflect.Slice {
+		fieldValue.SetBytes(b)
+
+		return nil
+	}
+
+	if len(b) != fieldValue.Len() {
+		return ctxerrors.Wrapf(
+			ErrLengthMismatch,
+			"expected %d bytes, got %d", fieldValue.Len(), len(b),
+		)
+	}
+
+	reflect.Copy(fieldValue, reflect.ValueOf(b))
+
+	return nil
+}
+
+// decodeBytes decodes val according to the `encoding` tag. Base64 input
+// is accepted with or without padding.
+func decodeBytes(val, encoding string) ([]byte, error) {
+	var (
+		b   []byte
+		err error
+	)
+
+	switch encoding {
+	case "", encodingRaw:
+		return []byte(val), nil
+	case encodingBase64:
+		b, err = base64.RawStdEncoding.DecodeString(strings.TrimRight(val, "="))
+	case encodingBase64URL:
+		b, err = base64.RawURLEncoding.DecodeString(strings.TrimRight(val, "="))
+	case encodingHex:
+		b, err = hex.DecodeString(val)
+	default:
+		return nil, ctxerrors.Wrapf(ErrUnknownEncoding, "%q", encoding)
+	}
+
+	if err != nil {
+		return nil, ctxerrors.Wrapf(err, "failed to decode %s", encoding)
+	}
+
+	return b, nil
+}
+
+// encodeBytes is the inverse of decodeBytes. Base64 output is padded.
+func encodeBytes(v reflect.Value, encoding string) (string, error) {
+	b := make([]byte, v.Len())
+	reflect.Copy(reflect.ValueOf(b), v)
+
+	switch encoding {
+	case "", encodingRaw:
+		return string(b), nil
+	case encodingBase64:
+		return base64.StdEncoding.EncodeToString(b), nil
+	case encodingBase64URL:
+		return base64.URLEncoding.EncodeToString(b), nil
+	case encodingHex:
+		return hex.EncodeToString(b), nil
+	default:
+		return "", ctxerrors.Wrapf(ErrUnknownEncoding, "%q", encoding)
+	}
+}
+
+func isByteSequence(typ reflect.Type) bool {
+	switch typ.Kind() { //nolint:exhaustive
+	case reflect.Slice, reflect.Array:
+		return typ.Elem().Kind() == reflect.Uint8
+	default:
+		return false
+	}
+}
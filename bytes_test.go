@@ -0,0 +1,97 @@
+package gonfiguration_test
+
+import (
+	"testing"
+
+	"github.com/psyb0t/gonfiguration"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBytes(t *testing.T) {
+	t.Run("encodings", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		type Config struct {
+			Raw       []byte `env:"RAW"`
+			Base64    []byte `env:"B64" encoding:"base64"`
+			Unpadded  []byte `env:"B64_UNPADDED" encoding:"base64"`
+			Base64URL []byte `env:"B64_URL" encoding:"base64url"`
+			Hex       []byte `env:"HEX" encoding:"hex"`
+			Default   []byte `env:"DEFAULT_KEY" encoding:"hex" default:"cafe"`
+		}
+
+		t.Setenv("RAW", "plain")
+		t.Setenv("B64", "aGk/Pz8=")
+		t.Setenv("B64_UNPADDED", "aGk/Pz8")
+		t.Setenv("B64_URL", "aGk_Pz8=")
+		t.Setenv("HEX", "DEADbeef")
+
+		cfg := Config{}
+		require.NoError(t, gonfiguration.Parse(&cfg))
+		require.Equal(t, []byte("plain"), cfg.Raw)
+		require.Equal(t, []byte("hi???"), cfg.Base64)
+		require.Equal(t, []byte("hi???"), cfg.Unpadded)
+		require.Equal(t, []byte("hi???"), cfg.Base64URL)
+		require.Equal(t, []byte{0xde, 0xad, 0xbe, 0xef}, cfg.Hex)
+		require.Equal(t, []byte{0xca, 0xfe}, cfg.Default)
+	})
+
+	t.Run("fixed size array", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		type Config struct {
+			Key [4]byte `env:"KEY" encoding:"hex"`
+		}
+
+		t.Setenv("KEY", "00010203")
+
+		cfg := Config{}
+		require.NoError(t, gonfiguration.Parse(&cfg))
+		require.Equal(t, [4]byte{0, 1, 2, 3}, cfg.Key)
+	})
+
+	t.Run("array length mismatch", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		type Config struct {
+			AESKey [32]byte `env:"AES_KEY" encoding:"base64"`
+		}
+
+		// 31 bytes
+		t.Setenv("AES_KEY", "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA")
+
+		cfg := Config{}
+		err := gonfiguration.Parse(&cfg)
+		require.ErrorIs(t, err, gonfiguration.ErrLengthMismatch)
+		require.Contains(t, err.Error(), "field AES_KEY")
+		require.Contains(t, err.Error(), "expected 32 bytes, got 31")
+	})
+
+	t.Run("invalid encoded value", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		type Config struct {
+			Secret []byte `env:"SECRET" encoding:"hex"`
+		}
+
+		t.Setenv("SECRET", "xyz")
+
+		cfg := Config{}
+		err := gonfiguration.Parse(&cfg)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "field SECRET")
+	})
+
+	t.Run("unknown encoding", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		type Config struct {
+			Secret []byte `env:"SECRET" encoding:"rot13"`
+		}
+
+		t.Setenv("SECRET", "abc")
+
+		cfg := Config{}
+		require.ErrorIs(t, gonfiguration.Parse(&cfg), gonfiguration.ErrUnknownEncoding)
+	})
+}
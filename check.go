@@ -0,0 +1,155 @@
+package gonfiguration
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/psyb0t/ctxerrors"
+)
+
+// Issue is one problem Check found with an env var.
+type Issue struct {
+	Key string
+	// Err says what's wrong, wrapping a sentinel such as
+	// ErrRequiredFieldNotSet. Secret values never appear in it.
+	Err error
+}
+
+// CheckReport lists what would stop, or should be fixed before, a Parse
+// of an environment.
+type CheckReport struct {
+	// Missing holds required keys with no value and no default.
+	Missing []Issue
+	// Invalid holds keys whose value or default fails to parse.
+	Invalid []Issue
+	// Unknown holds the keys WithStrict would reject; it's empty
+	// without WithStrict.
+	Unknown []Issue
+	// Deprecated holds deprecated aliases in use. Parse only warns about
+	// them, so they don't fail the check.
+	Deprecated []Issue
+}
+
+// Check runs the whole Parse pipeline for dst's type, a struct or a
+// pointer to one, into a throw-away value and reports every missing,
+// invalid, unknown and deprecated key rather than stopping at the first.
+// Neither dst nor the package state GetEnvVars and GetAllValues read is
+// touched, so it's safe to call before or alongside Parse. Pass
+// WithEnviron to check an environment other than the process's. The error
+// is for problems with the type itself, such as an unsupported field type.
+func Check(dst any, opts ...Option) (CheckReport, error) {
+	typ := reflect.TypeOf(dst)
+	if typ != nil && typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	if typ == nil || typ.Kind() != reflect.Struct {
+		return CheckReport{}, ErrDestinationNotStruct
+	}
+
+	envVars, err := newOptions(opts).envVars()
+	if err != nil {
+		return CheckReport{}, ctxerrors.Wrap(err, "failed to get env vars")
+	}
+
+	report := CheckReport{}
+
+	p := newParser(envVars, opts)
+	p.options.report = nil
+	p.check = &report
+	p.root = typ
+
+	if err := p.parseFields(reflect.New(typ).Elem(), p.options.prefix, ""); err != nil {
+		return report, ctxerrors.Wrap(err, "failed to check fields")
+	}
+
+	if p.options.strict {
+		report.Unknown = p.unknownKeys()
+	}
+
+	return report, nil
+}
+
+// OK reports whether Parse would succeed: nothing missing, invalid or
+// unknown.
+func (r CheckReport) OK() bool {
+	return len(r.Missing) == 0 && len(r.Invalid) == 0 && len(r.Unknown) == 0
+}
+
+// Err joins the missing, invalid and unknown issues into one error, nil
+// if the check is OK.
+func (r CheckReport) Err() error {
+	errs := []error{}
+
+	for _, issues := range [][]Issue{r.Missing, r.Invalid, r.Unknown} {
+		for _, issue := range issues {
+			errs = append(errs, issue.Err)
+		}
+	}
+
+	return ctxerrors.Join(errs...)
+}
+
+// String renders the report one issue per line, grouped by kind.
+func (r CheckReport) String() string {
+	var b strings.Builder
+
+	groups := []struct {
+		name   string
+		issues []Issue
+	}{
+		{"missing", r.Missing},
+		{"invalid", r.Invalid},
+		{"unknown", r.Unknown},
+		{"deprecated", r.Deprecated},
+	}
+
+	for _, group := range groups {
+		for _, issue := range group.issues {
+			fmt.Fprintf(&b, "%s %s: %v\n", group.name, issue.Key, issue.Err)
+		}
+	}
+
+	return b.String()
+}
+
+// fieldError returns err unchanged when parsing, or records it in the
+// check report and returns nil so Check moves on to the next field.
+// Unsupported field types always fail: they're a problem with the type,
+// not the environment.
+func (p *parser) fieldError(key string, err error) error {
+	if p.check == nil || errors.Is(err, ErrUnsupportedFieldType) {
+		return err
+	}
+
+	issue := Issue{Key: key, Err: err}
+
+	if errors.Is(err, ErrRequiredFieldNotSet) {
+		p.check.Missing = append(p.check.Missing, issue)
+	} else {
+		p.check.Invalid = append(p.check.Invalid, issue)
+	}
+
+	return nil
+}
+
+// warnDeprecated logs a deprecated alias in use, or records it when
+// checking.
+func (p *parser) warnDeprecated(key, replacement string) {
+	if p.check != nil {
+		p.check.Deprecated = append(p.check.Deprecated, Issue{
+			Key: key,
+			Err: ctxerrors.Wrapf(ErrDeprecatedKey, "%s, use %s", key, replacement),
+		})
+
+		return
+	}
+
+	p.logger().Warn(
+		"deprecated env var in use",
+		"key", key,
+		"replacement", replacement,
+	)
+}
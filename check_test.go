@@ -0,0 +1,137 @@
+package gonfiguration_test
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/psyb0t/gonfiguration"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheck(t *testing.T) {
+	type Upstream struct {
+		Host string `env:"HOST,required"`
+		Port int    `env:"PORT"`
+	}
+
+	type Config struct {
+		DBURL     string                       `env:"APP_DATABASE_URL,required,alias=APP_DB_URL,deprecated"`
+		Port      int                          `env:"APP_PORT" default:"8080"`
+		Level     string                       `env:"APP_LOG_LEVEL" oneof:"debug,info"`
+		Token     gonfiguration.Secret[int]    `env:"APP_PIN"`
+		APIKey    gonfiguration.Secret[string] `env:"APP_API_KEY,required"`
+		Upstreams []Upstream                   `env:"APP_UPSTREAM"`
+	}
+
+	t.Run("reports every problem", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		environ := []string{
+			"APP_DB_URL=postgres://db",
+			"APP_PORT=http",
+			"APP_PIN=s3cr3t",
+			"APP_UPSTREAM_0_PORT=x",
+			"APP_PROT=80",
+		}
+
+		report, err := gonfiguration.Check(
+			Config{},
+			gonfiguration.WithEnviron(environ),
+			gonfiguration.WithStrict("APP_"),
+		)
+		require.NoError(t, err)
+		require.False(t, report.OK())
+
+		keys := func(issues []gonfiguration.Issue) []string {
+			out := []string{}
+			for _, issue := range issues {
+				out = append(out, issue.Key)
+			}
+
+			return out
+		}
+
+		require.Equal(t, []string{"APP_API_KEY", "APP_UPSTREAM_0_HOST"}, keys(report.Missing))
+		require.Equal(t, []string{"APP_PORT", "APP_PIN", "APP_UPSTREAM_0_PORT"}, keys(report.Invalid))
+		require.Equal(t, []string{"APP_PROT"}, keys(report.Unknown))
+		require.Equal(t, []string{"APP_DB_URL"}, keys(report.Deprecated))
+
+		require.ErrorIs(t, report.Missing[0].Err, gonfiguration.ErrRequiredFieldNotSet)
+		require.NotContains(t, report.Invalid[1].Err.Error(), "s3cr3t")
+		require.ErrorContains(t, report.Unknown[0].Err, "did you mean APP_PORT?")
+		require.ErrorIs(t, report.Deprecated[0].Err, gonfiguration.ErrDeprecatedKey)
+
+		err = report.Err()
+		require.ErrorIs(t, err, gonfiguration.ErrRequiredFieldNotSet)
+		require.ErrorIs(t, err, gonfiguration.ErrUnknownKey)
+		require.NotErrorIs(t, err, gonfiguration.ErrDeprecatedKey)
+		require.Contains(t, report.String(), "deprecated APP_DB_URL: ")
+		require.Contains(t, report.String(), "missing APP_API_KEY: ")
+	})
+
+	t.Run("leaves package state alone", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		t.Setenv("APP_API_KEY", "k")
+		t.Setenv("APP_DATABASE_URL", "postgres://db")
+
+		var logs bytes.Buffer
+
+		report, err := gonfiguration.Check(&Config{}, gonfiguration.WithLogger(slog.New(slog.NewTextHandler(&logs, nil))))
+		require.NoError(t, err)
+		require.True(t, report.OK())
+		require.NoError(t, report.Err())
+		require.Empty(t, report.String())
+		require.Empty(t, logs.String())
+		require.Empty(t, gonfiguration.GetEnvVars())
+		require.Empty(t, gonfiguration.GetAllValues())
+	})
+
+	t.Run("deprecated alias is only a warning", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		report, err := gonfiguration.Check(&Config{}, gonfiguration.WithEnviron([]string{
+			"APP_DB_URL=postgres://db",
+			"APP_API_KEY=k",
+		}))
+		require.NoError(t, err)
+		require.True(t, report.OK())
+		require.Len(t, report.Deprecated, 1)
+	})
+
+	t.Run("type errors fail the check", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		_, err := gonfiguration.Check("nope")
+		require.ErrorIs(t, err, gonfiguration.ErrDestinationNotStruct)
+
+		type Bad struct {
+			Ch chan int `env:"CH"`
+		}
+
+		_, err = gonfiguration.Check(Bad{}, gonfiguration.WithEnviron(nil))
+		require.ErrorIs(t, err, gonfiguration.ErrUnsupportedFieldType)
+
+		_, err = gonfiguration.Check(Config{}, gonfiguration.WithEnviron([]string{"NOEQUALS"}))
+		require.ErrorIs(t, err, gonfiguration.ErrInvalidEnvVar)
+	})
+}
+
+func TestParseWithEnviron(t *testing.T) {
+	defer gonfiguration.Reset()
+
+	type Config struct {
+		Host string `env:"HOST"`
+		Port int    `env:"PORT"`
+	}
+
+	t.Setenv("HOST", "from-process")
+
+	cfg := Config{}
+	require.NoError(t, gonfiguration.Parse(&cfg,
+		gonfiguration.WithEnviron([]string{"HOST=base", "PORT=80"}),
+		gonfiguration.WithEnviron([]string{"PORT=8080"}),
+	))
+	require.Equal(t, Config{Host: "base", Port: 8080}, cfg)
+}
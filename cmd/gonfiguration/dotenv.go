@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/psyb0t/ctxerrors"
+)
+
+// readDotEnv reads a dotenv file as KEY=VALUE strings, in the format
+// gonfiguration writes: comments, blank lines and an optional export
+// prefix; values unquoted, single-quoted (literal) or double-quoted with
+// backslash escapes.
+func readDotEnv(path string) ([]string, error) {
+	f, err := os.Open(path) //nolint:gosec
+	if err != nil {
+		return nil, ctxerrors.Wrapf(err, "failed to open %s", path)
+	}
+	defer f.Close() //nolint:errcheck
+
+	environ, err := parseDotEnv(f)
+	if err != nil {
+		return nil, ctxerrors.Wrap(err, path)
+	}
+
+	return environ, nil
+}
+
+func parseDotEnv(r io.Reader) ([]string, error) {
+	environ := []string{}
+	scanner := bufio.NewScanner(r)
+
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		line = strings.TrimPrefix(line, "export ")
+
+		key, rawVal, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+
+		if !ok || key == "" || strings.ContainsAny(key, " \t") {
+			return nil, ctxerrors.Wrapf(errInvalidLine, "line %d", lineNum)
+		}
+
+		val, err := dotenvValue(strings.TrimSpace(rawVal))
+		if err != nil {
+			return nil, ctxerrors.Wrapf(err, "line %d", lineNum)
+		}
+
+		environ = append(environ, key+"="+val)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, ctxerrors.Wrap(err, "failed to read dotenv")
+	}
+
+	return environ, nil
+}
+
+// dotenvValue unquotes a raw value. Unquoted values end at a " #"
+// comment.
+func dotenvValue(raw string) (string, error) {
+	switch {
+	case strings.HasPrefix(raw, "'"):
+		val, _, ok := strings.Cut(raw[1:], "'")
+		if !ok {
+			return "", ctxerrors.Wrap(errInvalidLine, "unterminated single quote")
+		}
+
+		return val, nil
+	case strings.HasPrefix(raw, `"`):
+		return unescapeDoubleQuoted(raw[1:])
+	default:
+		if i := strings.Index(raw, " #"); i >= 0 {
+			raw = raw[:i]
+		}
+
+		return strings.TrimSpace(raw), nil
+	}
+}
+
+func unescapeDoubleQuoted(rest string) (string, error) {
+	var b strings.Builder
+
+	for i := 0; i < len(rest); i++ {
+		switch rest[i] {
+		case '"':
+			return b.String(), nil
+		case '\\':
+			i++
+			if i == len(rest) {
+				break
+			}
+
+			switch rest[i] {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			case 'r':
+				b.WriteByte('\r')
+			default:
+				b.WriteByte(rest[i])
+			}
+		default:
+			b.WriteByte(rest[i])
+		}
+	}
+
+	return "", ctxerrors.Wrap(errInvalidLine, "unterminated double quote")
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDotEnv(t *testing.T) {
+	environ, err := parseDotEnv(strings.NewReader(`
+# comment
+PLAIN=value # trailing comment
+export EXPORTED=yes
+SPACED = padded
+SINGLE='literal $HOME \n'
+DOUBLE="say \"hi\" \$USER\nnext\ttab\\"
+EMPTY=
+HASH=a#b
+`))
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"PLAIN=value",
+		"EXPORTED=yes",
+		"SPACED=padded",
+		`SINGLE=literal $HOME \n`,
+		"DOUBLE=say \"hi\" $USER\nnext\ttab\\",
+		"EMPTY=",
+		"HASH=a#b",
+	}, environ)
+}
+
+func TestParseDotEnvErrors(t *testing.T) {
+	testCases := []struct {
+		name  string
+		input string
+	}{
+		{name: "no equals", input: "KEY"},
+		{name: "empty key", input: "=value"},
+		{name: "space in key", input: "MY KEY=value"},
+		{name: "unterminated single quote", input: "KEY='value"},
+		{name: "unterminated double quote", input: `KEY="value`},
+		{name: "trailing backslash", input: `KEY="value\`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := parseDotEnv(strings.NewReader("OK=1\n" + tc.input))
+			require.ErrorIs(t, err, errInvalidLine)
+			require.ErrorContains(t, err, "line 2")
+		})
+	}
+}
@@ -0,0 +1,13 @@
+package main
+
+import "errors"
+
+var (
+	errUsage           = errors.New("usage")
+	errTypeNotFound    = errors.New("type not found")
+	errNotStruct       = errors.New("type is not a struct")
+	errUnsupportedType = errors.New("unsupported type")
+	errRecursiveType   = errors.New("recursive type")
+	errInvalidLine     = errors.New("invalid dotenv line")
+	errCheckFailed     = errors.New("check failed")
+)
@@ -0,0 +1,440 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/token"
+	"net"
+	"net/mail"
+	"net/netip"
+	"net/url"
+	"path"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/psyb0t/ctxerrors"
+)
+
+const gonfigurationPath = "github.com/psyb0t/gonfiguration"
+
+//nolint:gochecknoglobals
+var (
+	builtinTypes = map[string]reflect.Type{
+		"string":  reflect.TypeFor[string](),
+		"bool":    reflect.TypeFor[bool](),
+		"int":     reflect.TypeFor[int](),
+		"int8":    reflect.TypeFor[int8](),
+		"int16":   reflect.TypeFor[int16](),
+		"int32":   reflect.TypeFor[int32](),
+		"int64":   reflect.TypeFor[int64](),
+		"uint":    reflect.TypeFor[uint](),
+		"uint8":   reflect.TypeFor[uint8](),
+		"uint16":  reflect.TypeFor[uint16](),
+		"uint32":  reflect.TypeFor[uint32](),
+		"uint64":  reflect.TypeFor[uint64](),
+		"float32": reflect.TypeFor[float32](),
+		"float64": reflect.TypeFor[float64](),
+		"byte":    reflect.TypeFor[byte](),
+		"rune":    reflect.TypeFor[rune](),
+		"any":     reflect.TypeFor[any](),
+	}
+
+	// importedTypes are the types from other packages gonfiguration
+	// parses, keyed by import path and name.
+	importedTypes = map[string]reflect.Type{
+		"time.Duration":            reflect.TypeFor[time.Duration](),
+		"time.Time":                reflect.TypeFor[time.Time](),
+		"time.Location":            reflect.TypeFor[time.Location](),
+		"net/url.URL":              reflect.TypeFor[url.URL](),
+		"net.IP":                   reflect.TypeFor[net.IP](),
+		"net.IPNet":                reflect.TypeFor[net.IPNet](),
+		"net/netip.Addr":           reflect.TypeFor[netip.Addr](),
+		"net/netip.AddrPort":       reflect.TypeFor[netip.AddrPort](),
+		"net/netip.Prefix":         reflect.TypeFor[netip.Prefix](),
+		"net/mail.Address":         reflect.TypeFor[mail.Address](),
+		"regexp.Regexp":            reflect.TypeFor[regexp.Regexp](),
+		"text/template.Template":   reflect.TypeFor[template.Template](),
+		"crypto/tls.Certificate":   reflect.TypeFor[tls.Certificate](),
+		"crypto/x509.CertPool":     reflect.TypeFor[x509.CertPool](),
+		"encoding/json.RawMessage": reflect.TypeFor[json.RawMessage](),
+	}
+)
+
+// typeDecl is a type declared in the loaded package, with the imports
+// of the file it's declared in.
+type typeDecl struct {
+	spec    *ast.TypeSpec
+	imports map[string]string
+}
+
+// loader turns the struct types of a package's source into reflect types
+// gonfiguration can describe and check without compiling the package.
+// gonfiguration.Secret[T] fields become T fields with the secret env
+// option, and doc comments become desc tags where there is none.
+type loader struct {
+	decls    map[string]typeDecl
+	building map[string]bool
+	autoKeys bool
+}
+
+// loadStruct parses the Go files of the package in dir and returns the
+// reflect type of its struct type name. Fields are only resolved if
+// Parse would read them: fields with an env tag or, with autoKeys, every
+// exported field.
+func loadStruct(dir, name string, autoKeys bool) (reflect.Type, error) {
+	pkg, err := build.ImportDir(dir, 0)
+	if err != nil {
+		return nil, ctxerrors.Wrapf(err, "failed to load package %s", dir)
+	}
+
+	l := &loader{
+		decls:    map[string]typeDecl{},
+		building: map[string]bool{},
+		autoKeys: autoKeys,
+	}
+
+	fset := token.NewFileSet()
+
+	for _, fileName := range pkg.GoFiles {
+		file, err := parser.ParseFile(fset, filepath.Join(dir, fileName), nil, parser.ParseComments)
+		if err != nil {
+			return nil, ctxerrors.Wrapf(err, "failed to parse %s", fileName)
+		}
+
+		l.addDecls(file)
+	}
+
+	decl, ok := l.decls[name]
+	if !ok {
+		return nil, ctxerrors.Wrapf(errTypeNotFound, "%s in %s", name, dir)
+	}
+
+	if _, ok := decl.spec.Type.(*ast.StructType); !ok {
+		return nil, ctxerrors.Wrap(errNotStruct, name)
+	}
+
+	return l.localType(name)
+}
+
+func (l *loader) addDecls(file *ast.File) {
+	imports := map[string]string{}
+
+	for _, spec := range file.Imports {
+		importPath, _ := strconv.Unquote(spec.Path.Value)
+
+		name := path.Base(importPath)
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+
+		imports[name] = importPath
+	}
+
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.TYPE {
+			continue
+		}
+
+		for _, spec := range genDecl.Specs {
+			typeSpec, _ := spec.(*ast.TypeSpec)
+			if typeSpec.TypeParams == nil {
+				l.decls[typeSpec.Name.Name] = typeDecl{spec: typeSpec, imports: imports}
+			}
+		}
+	}
+}
+
+func (l *loader) localType(name string) (reflect.Type, error) {
+	decl, ok := l.decls[name]
+	if !ok {
+		return nil, ctxerrors.Wrap(errUnsupportedType, name)
+	}
+
+	if l.building[name] {
+		return nil, ctxerrors.Wrap(errRecursiveType, name)
+	}
+
+	l.building[name] = true
+	defer delete(l.building, name)
+
+	typ, _, err := l.typeOf(decl.spec.Type, decl.imports)
+	if err != nil {
+		return nil, ctxerrors.Wrapf(err, "type %s", name)
+	}
+
+	// A defined type over an imported one loses its setter, so Parse
+	// only sees the underlying kind. Aliases keep the imported type.
+	if _, ok := decl.spec.Type.(*ast.SelectorExpr); ok && !decl.spec.Assign.IsValid() {
+		typ, err = underlyingType(typ)
+		if err != nil {
+			return nil, ctxerrors.Wrapf(err, "type %s", name)
+		}
+	}
+
+	return typ, nil
+}
+
+// underlyingType returns the unnamed type of typ's kind, e.g. int64 for
+// time.Duration and []byte for net.IP. Structs have no such type Parse
+// could fill, so they're unsupported.
+func underlyingType(typ reflect.Type) (reflect.Type, error) {
+	if typ.Kind() == reflect.Slice {
+		return reflect.SliceOf(typ.Elem()), nil
+	}
+
+	builtin, ok := builtinTypes[typ.Kind().String()]
+	if !ok {
+		return nil, ctxerrors.Wrapf(errUnsupportedType, "defined type over %s", typ)
+	}
+
+	return builtin, nil
+}
+
+// typeOf resolves a type expression, reporting whether it was a
+// gonfiguration.Secret.
+func (l *loader) typeOf(expr ast.Expr, imports map[string]string) (reflect.Type, bool, error) {
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		if typ, ok := builtinTypes[expr.Name]; ok {
+			return typ, false, nil
+		}
+
+		typ, err := l.localType(expr.Name)
+
+		return typ, false, err
+	case *ast.SelectorExpr:
+		typ, err := importedType(expr, imports)
+
+		return typ, false, err
+	case *ast.IndexExpr:
+		if qualifiedName(expr.X, imports) != gonfigurationPath+".Secret" {
+			return nil, false, ctxerrors.Wrap(errUnsupportedType, "generic type")
+		}
+
+		typ, _, err := l.typeOf(expr.Index, imports)
+
+		return typ, true, err
+	case *ast.StarExpr:
+		elem, _, err := l.typeOf(expr.X, imports)
+		if err != nil {
+			return nil, false, err
+		}
+
+		return reflect.PointerTo(elem), false, nil
+	case *ast.ArrayType:
+		return l.arrayType(expr, imports)
+	case *ast.MapType:
+		key, _, err := l.typeOf(expr.Key, imports)
+		if err != nil {
+			return nil, false, err
+		}
+
+		elem, _, err := l.typeOf(expr.Value, imports)
+		if err != nil {
+			return nil, false, err
+		}
+
+		return reflect.MapOf(key, elem), false, nil
+	case *ast.StructType:
+		typ, err := l.structType(expr, imports)
+
+		return typ, false, err
+	case *ast.InterfaceType:
+		if len(expr.Methods.List) == 0 {
+			return builtinTypes["any"], false, nil
+		}
+	}
+
+	return nil, false, ctxerrors.Wrapf(errUnsupportedType, "%T", expr)
+}
+
+func (l *loader) arrayType(expr *ast.ArrayType, imports map[string]string) (reflect.Type, bool, error) {
+	elem, _, err := l.typeOf(expr.Elt, imports)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if expr.Len == nil {
+		return reflect.SliceOf(elem), false, nil
+	}
+
+	lit, ok := expr.Len.(*ast.BasicLit)
+	if !ok || lit.Kind != token.INT {
+		return nil, false, ctxerrors.Wrap(errUnsupportedType, "array length must be a literal")
+	}
+
+	length, err := strconv.ParseInt(lit.Value, 0, 0)
+	if err != nil {
+		return nil, false, ctxerrors.Wrap(err, "failed to parse array length")
+	}
+
+	return reflect.ArrayOf(int(length), elem), false, nil
+}
+
+func (l *loader) structType(expr *ast.StructType, imports map[string]string) (reflect.Type, error) {
+	fields := []reflect.StructField{}
+
+	for _, field := range expr.Fields.List {
+		tag := ""
+		if field.Tag != nil {
+			tag, _ = strconv.Unquote(field.Tag.Value)
+		}
+
+		names := []string{}
+		for _, name := range field.Names {
+			names = append(names, name.Name)
+		}
+
+		embedded := len(names) == 0
+		if embedded {
+			names = append(names, embeddedName(field.Type))
+		}
+
+		for _, name := range names {
+			envTag, hasEnv := reflect.StructTag(tag).Lookup("env")
+			if !ast.IsExported(name) || envTag == "-" || !hasEnv && !l.autoKeys {
+				continue
+			}
+
+			typ, secret, err := l.typeOf(field.Type, imports)
+			if err != nil && hasTagOption(envTag, "json") {
+				typ, err = builtinTypes["any"], nil
+			}
+
+			if err != nil {
+				return nil, ctxerrors.Wrapf(err, "field %s", name)
+			}
+
+			fields = append(fields, reflect.StructField{
+				Name:      name,
+				Type:      typ,
+				Tag:       fieldTag(tag, secret, field),
+				Anonymous: embedded,
+			})
+		}
+	}
+
+	return structOf(fields)
+}
+
+// hasTagOption reports whether the env tag has option, split and trimmed
+// the way gonfiguration parses it: the first part is the key, so an env
+// var named json doesn't count.
+func hasTagOption(envTag, option string) bool {
+	_, options, _ := strings.Cut(envTag, ",")
+
+	for part := range strings.SplitSeq(options, ",") {
+		if strings.TrimSpace(part) == option {
+			return true
+		}
+	}
+
+	return false
+}
+
+// structOf is reflect.StructOf, which panics on fields it can't build,
+// such as embedded types with methods.
+func structOf(fields []reflect.StructField) (reflect.Type, error) {
+	var (
+		typ reflect.Type
+		err error
+	)
+
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				err = ctxerrors.Wrapf(errUnsupportedType, "%v", r)
+			}
+		}()
+
+		typ = reflect.StructOf(fields)
+	}()
+
+	return typ, err
+}
+
+// fieldTag adds the secret env option to the tag of a Secret field and
+// a desc tag from the field's doc comment if it has none. reflect uses
+// the first occurrence of a key, so the rewritten env tag goes first.
+func fieldTag(tag string, secret bool, field *ast.Field) reflect.StructTag {
+	structTag := reflect.StructTag(tag)
+
+	if secret {
+		envTag := structTag.Get("env")
+		tag = `env:` + strconv.Quote(envTag+",secret") + " " + tag
+	}
+
+	if _, ok := structTag.Lookup("desc"); !ok {
+		if desc := docText(field); desc != "" {
+			tag += ` desc:` + strconv.Quote(desc)
+		}
+	}
+
+	return reflect.StructTag(strings.TrimSpace(tag))
+}
+
+// docText is the field's doc comment, or its line comment, on one line.
+func docText(field *ast.Field) string {
+	for _, group := range []*ast.CommentGroup{field.Doc, field.Comment} {
+		if text := strings.Join(strings.Fields(group.Text()), " "); text != "" {
+			return text
+		}
+	}
+
+	return ""
+}
+
+func importedType(expr *ast.SelectorExpr, imports map[string]string) (reflect.Type, error) {
+	name := qualifiedName(expr, imports)
+
+	typ, ok := importedTypes[name]
+	if !ok {
+		return nil, ctxerrors.Wrap(errUnsupportedType, name)
+	}
+
+	return typ, nil
+}
+
+// qualifiedName returns import/path.Name for a pkg.Name expression.
+func qualifiedName(expr ast.Expr, imports map[string]string) string {
+	sel, ok := expr.(*ast.SelectorExpr)
+	if !ok {
+		return ""
+	}
+
+	pkg, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return ""
+	}
+
+	importPath, ok := imports[pkg.Name]
+	if !ok {
+		importPath = pkg.Name
+	}
+
+	return fmt.Sprintf("%s.%s", importPath, sel.Sel.Name)
+}
+
+func embeddedName(expr ast.Expr) string {
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		return expr.Name
+	case *ast.StarExpr:
+		return embeddedName(expr.X)
+	case *ast.SelectorExpr:
+		return expr.Sel.Name
+	default:
+		return ""
+	}
+}
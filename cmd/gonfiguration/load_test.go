@@ -0,0 +1,95 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadStruct(t *testing.T) {
+	typ, err := loadStruct(testPkg, "Config", false)
+	require.NoError(t, err)
+
+	names := []string{}
+	for i := range typ.NumField() {
+		names = append(names, typ.Field(i).Name)
+	}
+
+	require.Equal(t, []string{"Port", "Level", "Timeout", "DBURL", "Token", "Upstreams", "Extra"}, names)
+
+	token, _ := typ.FieldByName("Token")
+	require.Equal(t, reflect.TypeFor[string](), token.Type)
+	require.Equal(t, "TOKEN,secret", token.Tag.Get("env"))
+
+	level, _ := typ.FieldByName("Level")
+	require.Equal(t, "Log verbosity.", level.Tag.Get("desc"))
+
+	timeout, _ := typ.FieldByName("Timeout")
+	require.Equal(t, "Request timeout.", timeout.Tag.Get("desc"))
+
+	extra, _ := typ.FieldByName("Extra")
+	require.Equal(t, reflect.TypeFor[any](), extra.Type)
+
+	upstreams, _ := typ.FieldByName("Upstreams")
+	require.Equal(t, reflect.Slice, upstreams.Type.Kind())
+	require.Equal(t, "Host of the upstream.", upstreams.Type.Elem().Field(0).Tag.Get("desc"))
+
+	typ, err = loadStruct(testPkg, "Config", true)
+	require.NoError(t, err)
+
+	_, hasRegion := typ.FieldByName("Region")
+	require.True(t, hasRegion)
+
+	_, hasIgnored := typ.FieldByName("Ignored")
+	require.False(t, hasIgnored)
+}
+
+func TestLoadStructDefinedTypes(t *testing.T) {
+	typ, err := loadStruct(testPkg, "Defined", false)
+	require.NoError(t, err)
+
+	timeout, _ := typ.FieldByName("Timeout")
+	require.Equal(t, reflect.TypeFor[int64](), timeout.Type)
+
+	wait, _ := typ.FieldByName("Wait")
+	require.Equal(t, reflect.TypeFor[time.Duration](), wait.Type)
+
+	addr, _ := typ.FieldByName("Addr")
+	require.Equal(t, reflect.TypeFor[[]byte](), addr.Type)
+}
+
+func TestHasTagOption(t *testing.T) {
+	require.True(t, hasTagOption("ROUTES,json", "json"))
+	require.True(t, hasTagOption("ROUTES, required , json", "json"))
+	require.False(t, hasTagOption("ROUTES,jsonx", "json"))
+	require.False(t, hasTagOption("ROUTES,alias=X,json_old", "json"))
+	require.False(t, hasTagOption("json", "json"))
+}
+
+func TestLoadStructErrors(t *testing.T) {
+	testCases := []struct {
+		name    string
+		dir     string
+		typ     string
+		errorIs error
+	}{
+		{name: "not found", dir: testPkg, typ: "Missing", errorIs: errTypeNotFound},
+		{name: "not a struct", dir: testPkg, typ: "NotAStruct", errorIs: errNotStruct},
+		{name: "unsupported field", dir: testPkg, typ: "Broken", errorIs: errUnsupportedType},
+		{name: "recursive", dir: testPkg, typ: "Loop", errorIs: errRecursiveType},
+		{name: "json lookalike options", dir: testPkg, typ: "NotJSON", errorIs: errUnsupportedType},
+		{name: "defined struct type", dir: testPkg, typ: "DefinedStruct", errorIs: errUnsupportedType},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := loadStruct(tc.dir, tc.typ, false)
+			require.ErrorIs(t, err, tc.errorIs)
+		})
+	}
+
+	_, err := loadStruct("testdata/nope", "Config", false)
+	require.Error(t, err)
+}
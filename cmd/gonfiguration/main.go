@@ -0,0 +1,231 @@
+// Command gonfiguration inspects a config struct from Go source: it
+// prints the env vars it reads, writes its .env.example and JSON Schema,
+// and checks an environment against it.
+//
+//	gonfiguration doc     [-pkg dir] [-format table|text|markdown] Config
+//	gonfiguration example [-pkg dir] [-o .env.example] Config
+//	gonfiguration schema  [-pkg dir] [-o file] Config
+//	gonfiguration check   [-pkg dir] [-env-file .env] [-strict PREFIX] Config
+//
+// Every command also takes -prefix and -auto-keys, the WithPrefix and
+// WithAutoKeys parse options.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+
+	"github.com/psyb0t/ctxerrors"
+	"github.com/psyb0t/gonfiguration"
+)
+
+const (
+	exitOK      = 0
+	exitFailure = 1
+	exitUsage   = 2
+
+	stdoutPath = "-"
+	fileMode   = 0o644
+)
+
+const usage = `usage: gonfiguration <command> [flags] <Type>
+
+commands:
+  doc      print the env vars the struct reads
+  example  write a .env.example
+  schema   write a JSON Schema
+  check    check the environment, or a dotenv file, against the struct
+
+Run gonfiguration <command> -h for a command's flags.
+`
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// command adds a subcommand's flags to fs and returns the function that
+// runs it against the loaded struct type, returning the exit code.
+type command func(fs *flag.FlagSet) func(cfg *config) (int, error)
+
+// config is the loaded struct type and the parse options the common
+// flags ask for.
+type config struct {
+	typ    reflect.Type
+	opts   []gonfiguration.Option
+	stdout io.Writer
+}
+
+//nolint:gochecknoglobals
+var commands = map[string]command{
+	"doc":     docFlags,
+	"example": exampleFlags,
+	"schema":  schemaFlags,
+	"check":   checkFlags,
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+
+		return exitUsage
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "unknown command %q\n\n%s", args[0], usage)
+
+		return exitUsage
+	}
+
+	fs := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	fs.SetOutput(stderr)
+
+	pkgDir := fs.String("pkg", ".", "directory of the package declaring the type")
+	prefix := fs.String("prefix", "", "prefix every key, like WithPrefix")
+	autoKeys := fs.Bool("auto-keys", false, "derive keys from field names, like WithAutoKeys")
+	runCmd := cmd(fs)
+
+	if err := fs.Parse(args[1:]); err != nil {
+		return exitUsage
+	}
+
+	if fs.NArg() != 1 {
+		fmt.Fprintf(stderr, "%s: expected exactly one type name\n", args[0])
+
+		return exitUsage
+	}
+
+	typ, err := loadStruct(*pkgDir, fs.Arg(0), *autoKeys)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: %v\n", args[0], err)
+
+		return exitFailure
+	}
+
+	cfg := &config{typ: typ, stdout: stdout}
+
+	if *prefix != "" {
+		cfg.opts = append(cfg.opts, gonfiguration.WithPrefix(*prefix))
+	}
+
+	if *autoKeys {
+		cfg.opts = append(cfg.opts, gonfiguration.WithAutoKeys())
+	}
+
+	code, err := runCmd(cfg)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: %v\n", args[0], err)
+	}
+
+	return code
+}
+
+// zero returns a zero value of the loaded type to pass as dst.
+func (c *config) zero() any {
+	return reflect.New(c.typ).Interface()
+}
+
+func docFlags(fs *flag.FlagSet) func(cfg *config) (int, error) {
+	format := fs.String("format", "table", "output format: table, text or markdown")
+
+	return func(cfg *config) (int, error) {
+		docs, err := gonfiguration.Describe(cfg.zero(), cfg.opts...)
+		if err != nil {
+			return exitFailure, err
+		}
+
+		render := map[string]func() string{
+			"table":    docs.Table,
+			"text":     docs.Text,
+			"markdown": docs.Markdown,
+		}[*format]
+
+		if render == nil {
+			return exitUsage, ctxerrors.Wrapf(errUsage, "unknown format %q", *format)
+		}
+
+		fmt.Fprint(cfg.stdout, render())
+
+		return exitOK, nil
+	}
+}
+
+func exampleFlags(fs *flag.FlagSet) func(cfg *config) (int, error) {
+	out := fs.String("o", ".env.example", `output file, "-" for stdout`)
+
+	return func(cfg *config) (int, error) {
+		docs, err := gonfiguration.Describe(cfg.zero(), cfg.opts...)
+		if err != nil {
+			return exitFailure, err
+		}
+
+		return writeOutput(cfg.stdout, *out, []byte(docs.EnvExample()))
+	}
+}
+
+func schemaFlags(fs *flag.FlagSet) func(cfg *config) (int, error) {
+	out := fs.String("o", stdoutPath, `output file, "-" for stdout`)
+
+	return func(cfg *config) (int, error) {
+		schema, err := gonfiguration.JSONSchema(cfg.zero(), cfg.opts...)
+		if err != nil {
+			return exitFailure, err
+		}
+
+		return writeOutput(cfg.stdout, *out, schema)
+	}
+}
+
+func checkFlags(fs *flag.FlagSet) func(cfg *config) (int, error) {
+	envFile := fs.String("env-file", "", "check this dotenv file instead of the environment")
+	strict := fs.String("strict", "", "report unknown keys with this prefix, like WithStrict")
+
+	return func(cfg *config) (int, error) {
+		opts := cfg.opts
+
+		if *envFile != "" {
+			environ, err := readDotEnv(*envFile)
+			if err != nil {
+				return exitFailure, err
+			}
+
+			opts = append(opts, gonfiguration.WithEnviron(environ))
+		}
+
+		if *strict != "" {
+			opts = append(opts, gonfiguration.WithStrict(*strict))
+		}
+
+		report, err := gonfiguration.Check(cfg.zero(), opts...)
+		if err != nil {
+			return exitFailure, err
+		}
+
+		fmt.Fprint(cfg.stdout, report)
+
+		if !report.OK() {
+			return exitFailure, errCheckFailed
+		}
+
+		fmt.Fprintln(cfg.stdout, "ok")
+
+		return exitOK, nil
+	}
+}
+
+func writeOutput(stdout io.Writer, path string, content []byte) (int, error) {
+	if path == stdoutPath {
+		_, _ = stdout.Write(content)
+
+		return exitOK, nil
+	}
+
+	if err := os.WriteFile(path, content, fileMode); err != nil {
+		return exitFailure, ctxerrors.Wrapf(err, "failed to write %s", path)
+	}
+
+	return exitOK, nil
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testPkg = "testdata/config"
+
+func runCommand(t *testing.T, args ...string) (int, string, string) {
+	t.Helper()
+
+	var stdout, stderr bytes.Buffer
+
+	code := run(args, &stdout, &stderr)
+
+	return code, stdout.String(), stderr.String()
+}
+
+func TestDoc(t *testing.T) {
+	code, stdout, stderr := runCommand(t, "doc", "-pkg", testPkg, "-format", "markdown", "Config")
+	require.Equal(t, exitOK, code, stderr)
+	require.Contains(t, stdout, "| `PORT` | `int` | `8080` | no |  | Port to listen on. |")
+	require.Contains(t, stdout, "| `LOG_LEVEL` | `string` | `info` | no | `debug`, `info` | Log verbosity. |")
+	require.Contains(t, stdout, "Request timeout.")
+	require.Contains(t, stdout, "| `DATABASE_URL` | `*url.URL` |  | yes |  | Database DSN. |")
+	require.Contains(t, stdout, "| `TOKEN` | `string` |  | no |  |  |")
+	require.NotContains(t, stdout, "REGION")
+	require.Contains(t, stdout, "| `UPSTREAM_<N>_HOST` | `string` |  | yes |  | Host of the upstream. |")
+	require.NotContains(t, stdout, "internal")
+
+	code, stdout, _ = runCommand(t, "doc", "-pkg", testPkg, "-prefix", "APP_", "Config")
+	require.Equal(t, exitOK, code)
+	require.Contains(t, stdout, "APP_PORT")
+
+	code, _, stderr = runCommand(t, "doc", "-pkg", testPkg, "-format", "html", "Config")
+	require.Equal(t, exitUsage, code)
+	require.Contains(t, stderr, `unknown format "html"`)
+}
+
+func TestExampleAndSchema(t *testing.T) {
+	out := filepath.Join(t.TempDir(), ".env.example")
+
+	code, _, stderr := runCommand(t, "example", "-pkg", testPkg, "-o", out, "Config")
+	require.Equal(t, exitOK, code, stderr)
+
+	content, err := os.ReadFile(out)
+	require.NoError(t, err)
+	require.Contains(t, string(content), "# Port to listen on.\n# int.\nPORT=8080\n")
+	require.Contains(t, string(content), "TOKEN=CHANGE_ME\n")
+
+	code, stdout, stderr := runCommand(t, "schema", "-pkg", testPkg, "-auto-keys", "Config")
+	require.Equal(t, exitOK, code, stderr)
+	require.Contains(t, stdout, `"$schema": "https://json-schema.org/draft/2020-12/schema"`)
+	require.Contains(t, stdout, `"writeOnly": true`)
+	require.Contains(t, stdout, `"REGION"`)
+	require.NotContains(t, stdout, `"IGNORED"`)
+
+	code, _, stderr = runCommand(t, "schema", "-pkg", testPkg, "-o", filepath.Join(out, "nope"), "Config")
+	require.Equal(t, exitFailure, code)
+	require.Contains(t, stderr, "failed to write")
+}
+
+func TestCheck(t *testing.T) {
+	dir := t.TempDir()
+
+	good := filepath.Join(dir, "good.env")
+	require.NoError(t, os.WriteFile(good, []byte(
+		"# comment\nexport DATABASE_URL=\"postgres://db\"\nUPSTREAM_0_HOST=a\nTOKEN='t0k3n'\n",
+	), 0o600))
+
+	code, stdout, stderr := runCommand(t, "check", "-pkg", testPkg, "-env-file", good, "Config")
+	require.Equal(t, exitOK, code, stderr)
+	require.Equal(t, "ok\n", stdout)
+
+	bad := filepath.Join(dir, "bad.env")
+	require.NoError(t, os.WriteFile(bad, []byte("PORT=http\nPROT=1\n"), 0o600))
+
+	code, stdout, stderr = runCommand(t, "check", "-pkg", testPkg, "-env-file", bad, "-strict", "P", "Config")
+	require.Equal(t, exitFailure, code)
+	require.Contains(t, stdout, "missing DATABASE_URL: ")
+	require.Contains(t, stdout, "invalid PORT: ")
+	require.Contains(t, stdout, "unknown PROT: ")
+	require.Contains(t, stderr, "check failed")
+
+	t.Setenv("DATABASE_URL", "postgres://db")
+
+	code, _, stderr = runCommand(t, "check", "-pkg", testPkg, "Config")
+	require.Equal(t, exitOK, code, stderr)
+
+	code, _, stderr = runCommand(t, "check", "-pkg", testPkg, "-env-file", filepath.Join(dir, "missing.env"), "Config")
+	require.Equal(t, exitFailure, code)
+	require.Contains(t, stderr, "failed to open")
+}
+
+func TestUsage(t *testing.T) {
+	code, _, stderr := runCommand(t)
+	require.Equal(t, exitUsage, code)
+	require.Contains(t, stderr, "usage: gonfiguration")
+
+	code, _, stderr = runCommand(t, "lint")
+	require.Equal(t, exitUsage, code)
+	require.Contains(t, stderr, `unknown command "lint"`)
+
+	code, _, _ = runCommand(t, "doc", "-nope")
+	require.Equal(t, exitUsage, code)
+
+	code, _, stderr = runCommand(t, "doc", "-pkg", testPkg)
+	require.Equal(t, exitUsage, code)
+	require.Contains(t, stderr, "expected exactly one type name")
+
+	code, _, stderr = runCommand(t, "doc", "-pkg", testPkg, "Missing")
+	require.Equal(t, exitFailure, code)
+	require.Contains(t, stderr, "type not found")
+
+	code, _, stderr = runCommand(t, "schema", "-pkg", testPkg, "Broken")
+	require.Equal(t, exitFailure, code)
+	require.Contains(t, stderr, "unsupported type")
+}
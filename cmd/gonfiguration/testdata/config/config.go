@@ -0,0 +1,67 @@
+// Package config is a sample config package for the command's tests.
+package config
+
+import (
+	"math/big"
+	"net"
+	"net/url"
+	"time"
+
+	gonfig "github.com/psyb0t/gonfiguration"
+)
+
+type Level string
+
+type Upstream struct {
+	// Host of the upstream.
+	Host string `env:"HOST,required"`
+	Port uint16 `env:"PORT" default:"80"`
+}
+
+type Config struct {
+	// Port to listen on.
+	Port    int           `env:"PORT" default:"8080"`
+	Level   Level         `env:"LOG_LEVEL" default:"info" oneof:"debug,info"` // Log verbosity.
+	Timeout time.Duration `env:"TIMEOUT" default:"30s" desc:"Request timeout."`
+	// Database DSN.
+	DBURL     *url.URL              `env:"DATABASE_URL,required"`
+	Token     gonfig.Secret[string] `env:"TOKEN"`
+	Upstreams []Upstream            `env:"UPSTREAM"`
+	Extra     map[string]*big.Int   `env:"EXTRA,json"`
+
+	Region   string
+	internal string
+	Ignored  chan int `env:"-"`
+}
+
+type Broken struct {
+	Ch chan int `env:"CH"`
+}
+
+type NotJSON struct {
+	Ch    chan int `env:"CH,jsonx"`
+	Alias chan int `env:"ALIAS,alias=OLD,json_old"`
+}
+
+type Loop struct {
+	Next []Loop `env:"NEXT"`
+}
+
+type NotAStruct int
+
+type (
+	Timeout time.Duration
+	Wait    = time.Duration
+	Addr    net.IP
+	Launch  time.Time
+)
+
+type Defined struct {
+	Timeout Timeout `env:"TIMEOUT"`
+	Wait    Wait    `env:"WAIT"`
+	Addr    Addr    `env:"ADDR"`
+}
+
+type DefinedStruct struct {
+	Launch Launch `env:"LAUNCH"`
+}
@@ -0,0 +1,382 @@
+package gonfiguration
+
+import (
+	"crypto/tls"
+	"fmt"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/psyb0t/ctxerrors"
+)
+
+const (
+	// indexPlaceholder and namePlaceholder stand in for the element index
+	// of a slice of structs and the entry name of a map of structs.
+	indexPlaceholder = "<N>"
+	namePlaceholder  = "<NAME>"
+)
+
+// VarDoc describes one env var a config struct reads.
+type VarDoc struct {
+	Key         string
+	Field       string
+	Type        string
+	Default     string
+	HasDefault  bool
+	Required    bool
+	Secret      bool
+	Deprecated  bool
+	Aliases     []string
+	Allowed     []string
+	Description string
+}
+
+// Docs lists the env vars of a config struct in field order.
+type Docs []VarDoc
+
+// Describe walks the type of dst, a struct or a pointer to one, and
+// documents every env var Parse would read with the same options: key,
+// Go type, default, whether it's required, allowed values (the oneof tag)
+// and the desc tag. Keys inside slices and maps of structs use <N> and
+// <NAME> placeholders. SetDefault values override tag defaults, as they
+// do in Parse; secret defaults are redacted.
+func Describe(dst any, opts ...Option) (Docs, error) {
+	vars, err := describeVars(dst, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	docs := make(Docs, len(vars))
+	for i, v := range vars {
+		docs[i] = v.VarDoc
+	}
+
+	return docs, nil
+}
+
+// describedVar is a VarDoc along with the field and value type it was
+// made from, for renderers that need more than the docs.
+type describedVar struct {
+	VarDoc
+
+	field reflect.StructField
+	typ   reflect.Type
+	tag   envTag
+}
+
+func describeVars(dst any, opts []Option) ([]describedVar, error) {
+	typ := reflect.TypeOf(dst)
+	if typ != nil && typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	if typ == nil || typ.Kind() != reflect.Struct {
+		return nil, ErrDestinationNotStruct
+	}
+
+	p := newParser(nil, opts)
+	p.describing = map[reflect.Type]bool{}
+	vars := []describedVar{}
+
+	if err := p.describeFields(typ, p.options.prefix, "", &vars); err != nil {
+		return nil, ctxerrors.Wrap(err, "failed to describe fields")
+	}
+
+	return vars, nil
+}
+
+func (p *parser) describeFields(typ reflect.Type, prefix, path string, vars *[]describedVar) error {
+	// Parse only follows a slice or map of structs as far as keys exist,
+	// but without an environment there's nothing to stop at
+	if p.describing[typ] {
+		return ctxerrors.Wrapf(ErrUnsupportedFieldType, "recursive type %s", typ)
+	}
+
+	p.describing[typ] = true
+	defer delete(p.describing, typ)
+
+	for i := range typ.NumField() {
+		field := typ.Field(i)
+
+		tag, ok := p.fieldTag(field)
+		if !ok {
+			continue
+		}
+
+		key := prefix + tag.key
+		fieldPath := path + field.Name
+		fieldType := field.Type
+
+		if inner, ok := secretInnerType(fieldType); ok {
+			fieldType = inner
+			tag.secret = true
+		}
+
+		var err error
+
+		switch {
+		case !tag.json && isIndexedStructSlice(fieldType):
+			elemPrefix := key + indexSeparator + indexPlaceholder + indexSeparator
+			err = p.describeFields(fieldType.Elem(), elemPrefix, fieldPath+"["+indexPlaceholder+"].", vars)
+		case !tag.json && isNamedStructMap(fieldType):
+			elemPrefix := key + indexSeparator + namePlaceholder + indexSeparator
+			err = p.describeFields(fieldType.Elem(), elemPrefix, fieldPath+"["+namePlaceholder+"].", vars)
+		case !tag.json && p.options.naming != nil && isNestedStruct(fieldType):
+			nestedPath := fieldPath + "."
+			if field.Anonymous {
+				nestedPath = path
+			}
+
+			err = p.describeFields(fieldType, p.nestedPrefix(prefix, tag), nestedPath, vars)
+		case !tag.json && !isSupportedValueType(fieldType):
+			err = ctxerrors.Wrapf(ErrUnsupportedFieldType, "field %s: %s", key, fieldType)
+		case fieldType == reflect.TypeFor[tls.Certificate]():
+			p.describeCertificate(field, prefix, fieldPath, tag, vars)
+		default:
+			*vars = append(*vars, describedVar{
+				VarDoc: describeVar(field, fieldType, prefix, fieldPath, tag),
+				field:  field,
+				typ:    fieldType,
+				tag:    tag,
+			})
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// describeCertificate documents a tls.Certificate field and, with the
+// tlskey tag, the env var holding its private key. Whichever var carries
+// the key is secret.
+func (p *parser) describeCertificate(
+	field reflect.StructField,
+	prefix, path string,
+	tag envTag,
+	vars *[]describedVar,
+) {
+	format := valueFormatFromField(field)
+	certTag := tag
+	certTag.secret = tag.secret || format.tlsKeyEnv == ""
+
+	*vars = append(*vars, describedVar{
+		VarDoc: describeVar(field, field.Type, prefix, path, certTag),
+		field:  field,
+		typ:    field.Type,
+		tag:    certTag,
+	})
+
+	if format.tlsKeyEnv == "" {
+		return
+	}
+
+	keyTag := envTag{key: format.tlsKeyEnv, required: tag.required, secret: true}
+	keyField := reflect.StructField{Name: field.Name, Type: reflect.TypeFor[string]()}
+
+	*vars = append(*vars, describedVar{
+		VarDoc: VarDoc{
+			Key:         prefix + format.tlsKeyEnv,
+			Field:       path,
+			Type:        keyField.Type.String(),
+			Required:    tag.required,
+			Secret:      true,
+			Description: "Private key of " + prefix + tag.key + ".",
+		},
+		field: keyField,
+		typ:   keyField.Type,
+		tag:   keyTag,
+	})
+}
+
+func describeVar(
+	field reflect.StructField,
+	fieldType reflect.Type,
+	prefix, path string,
+	tag envTag,
+) VarDoc {
+	key := prefix + tag.key
+	doc := VarDoc{
+		Key:         key,
+		Field:       path,
+		Type:        fieldType.String(),
+		Required:    tag.required,
+		Secret:      tag.secret,
+		Deprecated:  tag.deprecated,
+		Allowed:     valueFormatFromField(field).oneOf,
+		Description: field.Tag.Get("desc"),
+	}
+
+	for _, alias := range tag.aliases {
+		doc.Aliases = append(doc.Aliases, prefix+alias)
+	}
+
+	if tagDefault := tagDefaultFromField(field); tagDefault != nil {
+		doc.Default, doc.HasDefault = *tagDefault, true
+	}
+
+	if defaultValue := gonfig.getDefault(key); defaultValue != nil {
+		doc.Default, doc.HasDefault = fmt.Sprint(defaultValue), true
+	}
+
+	if doc.Secret && doc.HasDefault {
+		doc.Default = redacted
+	}
+
+	return doc
+}
+
+// secretInnerType returns T for a Secret[T] type.
+func secretInnerType(typ reflect.Type) (reflect.Type, bool) {
+	secretHolderType := reflect.TypeFor[secretHolder]()
+
+	if typ.Kind() != reflect.Struct || !reflect.PointerTo(typ).Implements(secretHolderType) {
+		return nil, false
+	}
+
+	return typ.Field(0).Type, true
+}
+
+// Text renders the docs as plain text, one block per env var, for a
+// --help screen.
+func (d Docs) Text() string {
+	var b strings.Builder
+
+	for i, doc := range d {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+
+		fmt.Fprintf(&b, "%s (%s)\n", doc.Key, strings.Join(doc.attributes(), ", "))
+
+		if doc.Description != "" {
+			fmt.Fprintf(&b, "    %s\n", doc.Description)
+		}
+
+		if len(doc.Allowed) > 0 {
+			fmt.Fprintf(&b, "    Allowed: %s\n", strings.Join(doc.Allowed, ", "))
+		}
+
+		if len(doc.Aliases) > 0 {
+			fmt.Fprintf(&b, "    Aliases: %s\n", strings.Join(doc.Aliases, ", "))
+		}
+	}
+
+	return b.String()
+}
+
+// Markdown renders the docs as a Markdown table.
+func (d Docs) Markdown() string {
+	var b strings.Builder
+
+	b.WriteString("| Key | Type | Default | Required | Allowed | Description |\n")
+	b.WriteString("| --- | --- | --- | --- | --- | --- |\n")
+
+	for _, doc := range d {
+		fmt.Fprintf(
+			&b, "| `%s` | `%s` | %s | %s | %s | %s |\n",
+			doc.Key,
+			doc.Type,
+			markdownCode(doc.Default, doc.HasDefault),
+			yesNo(doc.Required),
+			markdownCodes(doc.Allowed),
+			escapeMarkdownCell(doc.descriptionWithNotes()),
+		)
+	}
+
+	return b.String()
+}
+
+// Table renders the docs as columns aligned with spaces, for terminals.
+func (d Docs) Table() string {
+	var b strings.Builder
+
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0) //nolint:mnd
+
+	_, _ = fmt.Fprintln(w, "KEY\tTYPE\tDEFAULT\tREQUIRED\tALLOWED\tDESCRIPTION")
+
+	for _, doc := range d {
+		_, _ = fmt.Fprintf(
+			w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			doc.Key,
+			doc.Type,
+			doc.Default,
+			yesNo(doc.Required),
+			strings.Join(doc.Allowed, "|"),
+			doc.descriptionWithNotes(),
+		)
+	}
+
+	_ = w.Flush()
+
+	return b.String()
+}
+
+func (d VarDoc) attributes() []string {
+	attrs := []string{d.Type}
+
+	if d.HasDefault {
+		attrs = append(attrs, fmt.Sprintf("default %q", d.Default))
+	}
+
+	if d.Required {
+		attrs = append(attrs, "required")
+	}
+
+	if d.Secret {
+		attrs = append(attrs, "secret")
+	}
+
+	if d.Deprecated {
+		attrs = append(attrs, "aliases deprecated")
+	}
+
+	return attrs
+}
+
+func (d VarDoc) descriptionWithNotes() string {
+	desc := d.Description
+
+	if len(d.Aliases) > 0 {
+		note := "Aliases: " + strings.Join(d.Aliases, ", ")
+		if d.Deprecated {
+			note += " (deprecated)"
+		}
+
+		desc = strings.TrimSpace(desc + " " + note + ".")
+	}
+
+	return desc
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+
+	return "no"
+}
+
+func markdownCode(val string, ok bool) string {
+	if !ok {
+		return ""
+	}
+
+	return "`" + escapeMarkdownCell(val) + "`"
+}
+
+func markdownCodes(vals []string) string {
+	codes := make([]string, 0, len(vals))
+	for _, val := range vals {
+		codes = append(codes, markdownCode(val, true))
+	}
+
+	return strings.Join(codes, ", ")
+}
+
+func escapeMarkdownCell(val string) string {
+	return strings.ReplaceAll(val, "|", `\|`)
+}
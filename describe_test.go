@@ -0,0 +1,169 @@
+package gonfiguration_test
+
+import (
+	"crypto/tls"
+	"testing"
+	"time"
+
+	"github.com/psyb0t/gonfiguration"
+	"github.com/stretchr/testify/require"
+)
+
+type describeUpstream struct {
+	Host string `env:"HOST,required" desc:"Upstream host name."`
+}
+
+type describeConfig struct {
+	Port      int                          `env:"PORT" default:"8080" desc:"Port to listen on."`
+	LogLevel  string                       `env:"LOG_LEVEL" default:"info" oneof:"debug,info,warn" desc:"Log verbosity."`
+	Timeout   time.Duration                `env:"TIMEOUT"`
+	DBURL     string                       `env:"DATABASE_URL,required,alias=DB_URL,deprecated" desc:"Postgres DSN."`
+	Token     gonfiguration.Secret[string] `env:"TOKEN" default:"dev-token"`
+	Upstreams []describeUpstream           `env:"UPSTREAM"`
+}
+
+func TestDescribe(t *testing.T) {
+	t.Run("fields", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		docs, err := gonfiguration.Describe(describeConfig{})
+		require.NoError(t, err)
+		require.Equal(t, gonfiguration.Docs{
+			{
+				Key: "PORT", Field: "Port", Type: "int",
+				Default: "8080", HasDefault: true, Description: "Port to listen on.",
+			},
+			{
+				Key: "LOG_LEVEL", Field: "LogLevel", Type: "string",
+				Default: "info", HasDefault: true,
+				Allowed: []string{"debug", "info", "warn"}, Description: "Log verbosity.",
+			},
+			{Key: "TIMEOUT", Field: "Timeout", Type: "time.Duration"},
+			{
+				Key: "DATABASE_URL", Field: "DBURL", Type: "string", Required: true,
+				Deprecated: true, Aliases: []string{"DB_URL"}, Description: "Postgres DSN.",
+			},
+			{
+				Key: "TOKEN", Field: "Token", Type: "string",
+				Default: "[REDACTED]", HasDefault: true, Secret: true,
+			},
+			{
+				Key: "UPSTREAM_<N>_HOST", Field: "Upstreams[<N>].Host", Type: "string",
+				Required: true, Description: "Upstream host name.",
+			},
+		}, docs)
+	})
+
+	t.Run("prefix nesting and programmatic defaults", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		type Database struct {
+			Host string `desc:"Database host."`
+		}
+
+		type Tenant struct {
+			DSN string `env:"DSN"`
+		}
+
+		type NestedConfig struct {
+			HTTPPort int
+			DB       Database
+			Tenants  map[string]Tenant `env:"TENANT"`
+		}
+
+		gonfiguration.SetDefault("APP_HTTP_PORT", 9000)
+
+		docs, err := gonfiguration.Describe(&NestedConfig{}, gonfiguration.WithPrefix("APP_"), gonfiguration.WithAutoKeys())
+		require.NoError(t, err)
+		require.Len(t, docs, 3)
+		require.Equal(t, "APP_HTTP_PORT", docs[0].Key)
+		require.Equal(t, "9000", docs[0].Default)
+		require.Equal(t, "APP_DB_HOST", docs[1].Key)
+		require.Equal(t, "DB.Host", docs[1].Field)
+		require.Equal(t, "APP_TENANT_<NAME>_DSN", docs[2].Key)
+	})
+
+	t.Run("invalid destination", func(t *testing.T) {
+		_, err := gonfiguration.Describe(42)
+		require.ErrorIs(t, err, gonfiguration.ErrDestinationNotStruct)
+	})
+
+	t.Run("unsupported field", func(t *testing.T) {
+		type BadConfig struct {
+			Handler func() `env:"HANDLER"`
+		}
+
+		_, err := gonfiguration.Describe(BadConfig{})
+		require.ErrorIs(t, err, gonfiguration.ErrUnsupportedFieldType)
+	})
+}
+
+func TestDocsRenderers(t *testing.T) {
+	defer gonfiguration.Reset()
+
+	docs, err := gonfiguration.Describe(describeConfig{})
+	require.NoError(t, err)
+
+	t.Run("text", func(t *testing.T) {
+		text := docs.Text()
+		require.Contains(t, text, "PORT (int, default \"8080\")\n    Port to listen on.\n")
+		require.Contains(t, text, "    Allowed: debug, info, warn\n")
+		require.Contains(t, text, "DATABASE_URL (string, required, aliases deprecated)\n")
+		require.Contains(t, text, "    Aliases: DB_URL\n")
+		require.NotContains(t, text, "dev-token")
+	})
+
+	t.Run("markdown", func(t *testing.T) {
+		md := docs.Markdown()
+		require.Contains(t, md, "| Key | Type | Default | Required | Allowed | Description |\n")
+		require.Contains(t, md, "| `LOG_LEVEL` | `string` | `info` | no | `debug`, `info`, `warn` | Log verbosity. |\n")
+		require.Contains(t, md, "| `DATABASE_URL` | `string` |  | yes |  | Postgres DSN. Aliases: DB_URL (deprecated). |\n")
+	})
+
+	t.Run("table", func(t *testing.T) {
+		table := docs.Table()
+		require.Contains(t, table, "KEY                ")
+		require.Contains(t, table, "debug|info|warn")
+		require.Contains(t, table, "UPSTREAM_<N>_HOST")
+	})
+}
+
+func TestDescribeRecursiveType(t *testing.T) {
+	defer gonfiguration.Reset()
+
+	type Node struct {
+		Name     string          `env:"NAME"`
+		Children []Node          `env:"CHILD"`
+		Named    map[string]Node `env:"NAMED"`
+	}
+
+	_, err := gonfiguration.Describe(Node{})
+	require.ErrorIs(t, err, gonfiguration.ErrUnsupportedFieldType)
+	require.ErrorContains(t, err, "recursive type")
+
+	_, err = gonfiguration.JSONSchema(Node{})
+	require.ErrorIs(t, err, gonfiguration.ErrUnsupportedFieldType)
+}
+
+func TestDescribeCertificate(t *testing.T) {
+	defer gonfiguration.Reset()
+
+	type Config struct {
+		Cert   tls.Certificate `env:"TLS_CERT,required" tlskey:"TLS_KEY"`
+		Bundle tls.Certificate `env:"TLS_BUNDLE" default:"/etc/bundle.pem"`
+	}
+
+	docs, err := gonfiguration.Describe(Config{}, gonfiguration.WithPrefix("APP_"))
+	require.NoError(t, err)
+	require.Equal(t, gonfiguration.Docs{
+		{Key: "APP_TLS_CERT", Field: "Cert", Type: "tls.Certificate", Required: true},
+		{
+			Key: "APP_TLS_KEY", Field: "Cert", Type: "string", Required: true, Secret: true,
+			Description: "Private key of APP_TLS_CERT.",
+		},
+		{
+			Key: "APP_TLS_BUNDLE", Field: "Bundle", Type: "tls.Certificate", Secret: true,
+			Default: "[REDACTED]", HasDefault: true,
+		},
+	}, docs)
+}
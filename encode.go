@@ -0,0 +1,515 @@
+package gonfiguration
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math"
+	"net"
+	"net/mail"
+	"net/netip"
+	"net/url"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/psyb0t/ctxerrors"
+)
+
+// valueFormatter is the inverse of a valueSetter: it renders a field
+// value as the env string its setter would parse back.
+type valueFormatter func(v reflect.Value, format valueFormat) (string, error)
+
+//nolint:gochecknoglobals
+var typeFormatters = map[reflect.Type]valueFormatter{
+	reflect.TypeFor[time.Duration]():      formatStringer,
+	reflect.TypeFor[time.Time]():          formatTime,
+	reflect.TypeFor[*time.Location]():     formatStringer,
+	reflect.TypeFor[url.URL]():            formatStringer,
+	reflect.TypeFor[*url.URL]():           formatStringer,
+	reflect.TypeFor[net.IP]():             formatStringer,
+	reflect.TypeFor[net.IPNet]():          formatStringer,
+	reflect.TypeFor[*net.IPNet]():         formatStringer,
+	reflect.TypeFor[netip.Addr]():         formatStringer,
+	reflect.TypeFor[netip.AddrPort]():     formatStringer,
+	reflect.TypeFor[netip.Prefix]():       formatStringer,
+	reflect.TypeFor[mail.Address]():       formatStringer,
+	reflect.TypeFor[*mail.Address]():      formatStringer,
+	reflect.TypeFor[*regexp.Regexp]():     formatStringer,
+	reflect.TypeFor[*template.Template](): formatTemplate,
+	reflect.TypeFor[tls.Certificate]():    formatCertificate,
+}
+
+//nolint:gochecknoglobals
+var (
+	yamlPlainKey      = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	yamlReservedWords = []string{"y", "n", "yes", "no", "on", "off", "true", "false", "null"}
+)
+
+// envEntry is one encoded env var. value is the env string; typed is the
+// same value for JSON and YAML: a number or bool for scalar kinds, a list
+// for delimited lists and the env string for everything else.
+type envEntry struct {
+	key   string
+	value string
+	typed any
+}
+
+// MarshalEnviron encodes src, a populated config struct or a pointer to
+// one, as KEY=VALUE strings in field order, ready for exec.Cmd.Env. Keys
+// follow the same options as Parse and values use the formats Parse reads,
+// so parsing the result gives src back. Secret values are [REDACTED]
+// unless WithSecretsRevealed is passed. Nil pointers, slices and maps are
+// left out, and so are *x509.CertPool fields, which can't be read back.
+func MarshalEnviron(src any, opts ...Option) ([]string, error) {
+	entries, err := encode(src, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	environ := make([]string, len(entries))
+	for i, entry := range entries {
+		environ[i] = entry.key + "=" + entry.value
+	}
+
+	return environ, nil
+}
+
+// MarshalDotEnv is MarshalEnviron as dotenv text, quoting values that
+// need it.
+func MarshalDotEnv(src any, opts ...Option) (string, error) {
+	entries, err := encode(src, opts)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+
+	for _, entry := range entries {
+		b.WriteString(entry.key + "=" + dotenvValue(entry.value) + "\n")
+	}
+
+	return b.String(), nil
+}
+
+// MarshalJSON encodes src as a JSON object keyed by env key, in field
+// order, with numbers, bools and lists as their JSON types.
+func MarshalJSON(src any, opts ...Option) ([]byte, error) {
+	entries, err := encode(src, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+
+	b.WriteString("{")
+
+	for i, entry := range entries {
+		if i > 0 {
+			b.WriteString(",")
+		}
+
+		key, _ := json.Marshal(entry.key)
+
+		val, err := json.Marshal(entry.typed)
+		if err != nil {
+			return nil, ctxerrors.Wrapf(err, "field %s", entry.key)
+		}
+
+		b.Write(key)
+		b.WriteString(":")
+		b.Write(val)
+	}
+
+	b.WriteString("}")
+
+	var out bytes.Buffer
+	if err := json.Indent(&out, b.Bytes(), "", "  "); err != nil {
+		return nil, ctxerrors.Wrap(err, "failed to indent JSON")
+	}
+
+	out.WriteString("\n")
+
+	return out.Bytes(), nil
+}
+
+// MarshalYAML encodes src as a YAML mapping keyed by env key, in field
+// order, with the same value types as MarshalJSON.
+func MarshalYAML(src any, opts ...Option) ([]byte, error) {
+	entries, err := encode(src, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+
+	for _, entry := range entries {
+		items, isList := entry.typed.([]any)
+
+		switch {
+		case !isList:
+			fmt.Fprintf(&b, "%s: %s\n", yamlKey(entry.key), yamlScalar(entry.typed))
+		case len(items) == 0:
+			fmt.Fprintf(&b, "%s: []\n", yamlKey(entry.key))
+		default:
+			fmt.Fprintf(&b, "%s:\n", yamlKey(entry.key))
+
+			for _, item := range items {
+				fmt.Fprintf(&b, "  - %s\n", yamlScalar(item))
+			}
+		}
+	}
+
+	return b.Bytes(), nil
+}
+
+func encode(src any, opts []Option) ([]envEntry, error) {
+	srcVal, err := getSrcStructValue(src)
+	if err != nil {
+		return nil, ctxerrors.Wrap(err, "invalid source")
+	}
+
+	p := newParser(nil, opts)
+	entries := []envEntry{}
+
+	if err := p.encodeFields(srcVal, p.options.prefix, &entries); err != nil {
+		return nil, ctxerrors.Wrap(err, "failed to encode fields")
+	}
+
+	return entries, nil
+}
+
+// getSrcStructValue returns an addressable copy of the struct src holds
+// or points to, so Secret fields can be unwrapped.
+func getSrcStructValue(src any) (reflect.Value, error) {
+	val := reflect.ValueOf(src)
+	if val.Kind() == reflect.Pointer {
+		if val.IsNil() {
+			return reflect.Value{}, ErrNilDestination
+		}
+
+		val = val.Elem()
+	}
+
+	if val.Kind() != reflect.Struct {
+		return reflect.Value{}, ErrDestinationNotStruct
+	}
+
+	addressable := reflect.New(val.Type()).Elem()
+	addressable.Set(val)
+
+	return addressable, nil
+}
+
+// encodeFields mirrors parseFields, appending an entry per field instead
+// of filling it.
+func (p *parser) encodeFields(srcVal reflect.Value, prefix string, entries *[]envEntry) error {
+	for i := range srcVal.NumField() {
+		fieldType := srcVal.Type().Field(i)
+
+		tag, ok := p.fieldTag(fieldType)
+		if !ok {
+			continue
+		}
+
+		key := prefix + tag.key
+		fieldValue := srcVal.Field(i)
+
+		if inner, ok := unwrapSecret(fieldValue); ok {
+			fieldValue = inner
+			tag.secret = true
+		}
+
+		// A pool can't give its certificates back, so there's nothing to
+		// write for it
+		if !tag.json && (isNilValue(fieldValue) || fieldValue.Type() == reflect.TypeFor[*x509.CertPool]()) {
+			continue
+		}
+
+		var err error
+
+		switch {
+		case !tag.json && isIndexedStructSlice(fieldValue.Type()):
+			for index := range fieldValue.Len() {
+				elemPrefix := key + indexSeparator + strconv.Itoa(index) + indexSeparator
+				if err = p.encodeFields(fieldValue.Index(index), elemPrefix, entries); err != nil {
+					break
+				}
+			}
+		case !tag.json && isNamedStructMap(fieldValue.Type()):
+			err = p.encodeNamedStructMap(fieldValue, key, tag, entries)
+		case !tag.json && p.options.naming != nil && isNestedStruct(fieldValue.Type()):
+			err = p.encodeFields(fieldValue, p.nestedPrefix(prefix, tag), entries)
+		default:
+			err = p.encodeField(fieldType, fieldValue, prefix, tag, entries)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *parser) encodeNamedStructMap(
+	fieldValue reflect.Value,
+	key string,
+	tag envTag,
+	entries *[]envEntry,
+) error {
+	names := map[string]reflect.Value{}
+	mapKeys := map[string]string{}
+
+	for iter := fieldValue.MapRange(); iter.Next(); {
+		mapKey := iter.Key().String()
+
+		name := mapKey
+		if !tag.keepCase {
+			name = strings.ToUpper(name)
+		}
+
+		if other, ok := mapKeys[name]; ok {
+			first, second := min(other, mapKey), max(other, mapKey)
+
+			return ctxerrors.Wrapf(
+				ErrDuplicateMapKey,
+				"field %s: %q and %q both encode as %s", key, first, second, name,
+			)
+		}
+
+		names[name] = iter.Value()
+		mapKeys[name] = mapKey
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(names)) {
+		elem := reflect.New(fieldValue.Type().Elem()).Elem()
+		elem.Set(names[name])
+
+		if err := p.encodeFields(elem, key+indexSeparator+name+indexSeparator, entries); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *parser) encodeField(
+	fieldType reflect.StructField,
+	fieldValue reflect.Value,
+	prefix string,
+	tag envTag,
+	entries *[]envEntry,
+) error {
+	key := prefix + tag.key
+	format := valueFormatFromField(fieldType)
+	format.json = tag.json
+
+	if !tag.json && !isSupportedType(fieldValue) {
+		return ctxerrors.Wrapf(ErrUnsupportedFieldType, "field %s", key)
+	}
+
+	value, err := formatValue(fieldValue, format)
+	if err != nil {
+		return ctxerrors.Wrapf(err, "field %s", key)
+	}
+
+	typed := typedValue(fieldValue, format, value)
+
+	// A certificate carries its private key, inline or under tlskey
+	cert, isCert := fieldValue.Interface().(tls.Certificate)
+	if isCert && format.tlsKeyEnv == "" {
+		tag.secret = true
+	}
+
+	p.appendEntry(entries, envEntry{key: key, value: value, typed: typed}, tag.secret)
+
+	if !isCert || format.tlsKeyEnv == "" {
+		return nil
+	}
+
+	keyPEM, err := formatPrivateKey(cert)
+	if err != nil {
+		return ctxerrors.Wrapf(err, "field %s", key)
+	}
+
+	p.appendEntry(entries, envEntry{key: prefix + format.tlsKeyEnv, value: keyPEM, typed: keyPEM}, true)
+
+	return nil
+}
+
+func (p *parser) appendEntry(entries *[]envEntry, entry envEntry, secret bool) {
+	if secret && !p.options.revealSecrets {
+		entry.value, entry.typed = redacted, redacted
+	}
+
+	*entries = append(*entries, entry)
+}
+
+// formatValue is the inverse of setEnvVarValue.
+func formatValue(v reflect.Value, format valueFormat) (string, error) {
+	if format.json {
+		b, err := json.Marshal(v.Interface())
+		if err != nil {
+			return "", ctxerrors.Wrap(err, "failed to encode JSON")
+		}
+
+		return string(b), nil
+	}
+
+	if formatter, ok := typeFormatters[v.Type()]; ok {
+		if isNilValue(v) {
+			return "", nil
+		}
+
+		return formatter(v, format)
+	}
+
+	if isByteSequence(v.Type()) {
+		return encodeBytes(v, format.encoding)
+	}
+
+	switch v.Kind() { //nolint:exhaustive
+	case reflect.Slice, reflect.Array:
+		items := make([]string, v.Len())
+
+		for i := range v.Len() {
+			item, err := formatValue(v.Index(i), format)
+			if err != nil {
+				return "", ctxerrors.Wrapf(err, "item %d", i)
+			}
+
+			items[i] = item
+		}
+
+		return joinList(items, format), nil
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits()), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	default:
+		return "", ctxerrors.Wrapf(ErrUnsupportedFieldType, "%s cannot be encoded", v.Type())
+	}
+}
+
+// typedValue returns the JSON/YAML form of v, whose env form is value.
+func typedValue(v reflect.Value, format valueFormat, value string) any {
+	if format.json {
+		return value
+	}
+
+	if _, ok := typeFormatters[v.Type()]; ok {
+		return value
+	}
+
+	if isDelimitedList(v.Type()) {
+		items := make([]any, v.Len())
+
+		for i := range v.Len() {
+			item, _ := formatValue(v.Index(i), format)
+			items[i] = typedValue(v.Index(i), format, item)
+		}
+
+		return items
+	}
+
+	switch v.Kind() { //nolint:exhaustive
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return v.Uint()
+	case reflect.Float32, reflect.Float64:
+		// Go through the shortest text form at the field's own precision
+		// so a float32 0.1 stays 0.1 rather than its float64 widening
+		f, err := strconv.ParseFloat(strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits()), 64)
+		if err != nil {
+			return v.Float()
+		}
+
+		return f
+	case reflect.Bool:
+		return v.Bool()
+	default:
+		return value
+	}
+}
+
+// formatStringer formats types whose String output their setter parses
+// back. url.URL, net.IPNet and mail.Address only have String on the
+// pointer, so addressable values are formatted through their address.
+func formatStringer(v reflect.Value, _ valueFormat) (string, error) {
+	if v.Kind() != reflect.Pointer && v.CanAddr() {
+		v = v.Addr()
+	}
+
+	stringer, ok := v.Interface().(fmt.Stringer)
+	if !ok {
+		return "", ctxerrors.Wrapf(ErrUnsupportedFieldType, "%s cannot be encoded", v.Type())
+	}
+
+	return stringer.String(), nil
+}
+
+func isNilValue(v reflect.Value) bool {
+	switch v.Kind() { //nolint:exhaustive
+	case reflect.Pointer, reflect.Slice, reflect.Map, reflect.Interface:
+		return v.IsNil()
+	default:
+		return false
+	}
+}
+
+func yamlScalar(val any) string {
+	switch typed := val.(type) {
+	case string:
+		return yamlString(typed)
+	case float64:
+		switch {
+		case math.IsNaN(typed):
+			return ".nan"
+		case math.IsInf(typed, 1):
+			return ".inf"
+		case math.IsInf(typed, -1):
+			return "-.inf"
+		}
+
+		return strconv.FormatFloat(typed, 'g', -1, 64)
+	default:
+		return fmt.Sprint(typed)
+	}
+}
+
+// yamlKey leaves env-style keys plain and quotes anything a YAML parser
+// could read as something other than that string, e.g. "TRUE" or "1".
+func yamlKey(key string) string {
+	if yamlPlainKey.MatchString(key) && !slices.Contains(yamlReservedWords, strings.ToLower(key)) {
+		return key
+	}
+
+	return yamlString(key)
+}
+
+// yamlString double-quotes s. JSON string escapes are valid in YAML
+// double-quoted scalars, so the JSON encoder does the escaping.
+func yamlString(s string) string {
+	var b bytes.Buffer
+
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(s)
+
+	return strings.TrimSuffix(b.String(), "\n")
+}
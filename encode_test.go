@@ -0,0 +1,303 @@
+package gonfiguration_test
+
+import (
+	"crypto/x509"
+	"net/mail"
+	"net/netip"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/psyb0t/gonfiguration"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshalEnvironRoundTrip(t *testing.T) {
+	defer gonfiguration.Reset()
+
+	type Upstream struct {
+		Host string `env:"HOST"`
+		Port int    `env:"PORT"`
+	}
+
+	type Tenant struct {
+		DSN string `env:"DSN"`
+	}
+
+	type Config struct {
+		Name      string            `env:"NAME"`
+		Timeout   time.Duration     `env:"TIMEOUT"`
+		Launch    time.Time         `env:"LAUNCH" layout:"2006-01-02"`
+		Ratio     float64           `env:"RATIO"`
+		Debug     bool              `env:"DEBUG"`
+		Tags      []string          `env:"TAGS"`
+		Paths     []string          `env:"PATHS" sep:":"`
+		Ports     []uint16          `env:"PORTS"`
+		Labels    map[string]string `env:"LABELS,json"`
+		Upstreams []Upstream        `env:"UPSTREAM"`
+		Tenants   map[string]Tenant `env:"TENANT"`
+		Seed      []byte            `env:"SEED" encoding:"base64"`
+	}
+
+	src := Config{
+		Name:      "api",
+		Timeout:   90 * time.Second,
+		Launch:    time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC),
+		Ratio:     0.25,
+		Debug:     true,
+		Tags:      []string{"a,b", " padded ", `say "hi"`, ""},
+		Paths:     []string{"/usr/bin", "/bin"},
+		Ports:     []uint16{80, 443},
+		Labels:    map[string]string{"team": "core"},
+		Upstreams: []Upstream{{Host: "a", Port: 1}, {Host: "b", Port: 2}},
+		Tenants:   map[string]Tenant{"acme": {DSN: "postgres://acme"}},
+		Seed:      []byte{0, 1, 2, 255},
+	}
+
+	environ, err := gonfiguration.MarshalEnviron(&src)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"NAME=api",
+		"TIMEOUT=1m30s",
+		"LAUNCH=2026-03-01",
+		"RATIO=0.25",
+		"DEBUG=true",
+		`TAGS="a,b"," padded ","say ""hi""",""`,
+		"PATHS=/usr/bin:/bin",
+		"PORTS=80,443",
+		`LABELS={"team":"core"}`,
+		"UPSTREAM_0_HOST=a",
+		"UPSTREAM_0_PORT=1",
+		"UPSTREAM_1_HOST=b",
+		"UPSTREAM_1_PORT=2",
+		"TENANT_ACME_DSN=postgres://acme",
+		"SEED=AAEC/w==",
+	}, environ)
+
+	for _, kv := range environ {
+		key, val, _ := strings.Cut(kv, "=")
+		t.Setenv(key, val)
+	}
+
+	dst := Config{}
+	require.NoError(t, gonfiguration.Parse(&dst))
+	require.Equal(t, src, dst)
+}
+
+func TestMarshalEnvironTypes(t *testing.T) {
+	defer gonfiguration.Reset()
+
+	type Config struct {
+		Endpoint url.URL        `env:"ENDPOINT"`
+		Listen   netip.AddrPort `env:"LISTEN"`
+		Admin    mail.Address   `env:"ADMIN"`
+		Pattern  *regexp.Regexp `env:"PATTERN"`
+		TZ       *time.Location `env:"TZ"`
+		Seen     time.Time      `env:"SEEN" layout:"unix"`
+	}
+
+	endpoint, err := url.Parse("https://api.example.com/v1")
+	require.NoError(t, err)
+
+	src := Config{
+		Endpoint: *endpoint,
+		Listen:   netip.MustParseAddrPort("127.0.0.1:9090"),
+		Admin:    mail.Address{Name: "Ops", Address: "ops@example.com"},
+		Pattern:  regexp.MustCompile(`^v\d+$`),
+		TZ:       time.UTC,
+		Seen:     time.Unix(1700000000, 0).UTC(),
+	}
+
+	environ, err := gonfiguration.MarshalEnviron(src)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"ENDPOINT=https://api.example.com/v1",
+		"LISTEN=127.0.0.1:9090",
+		`ADMIN="Ops" <ops@example.com>`,
+		`PATTERN=^v\d+$`,
+		"TZ=UTC",
+		"SEEN=1700000000",
+	}, environ)
+}
+
+func TestMarshalSecrets(t *testing.T) {
+	defer gonfiguration.Reset()
+
+	type Config struct {
+		User     string                       `env:"DB_USER"`
+		Password string                       `env:"DB_PASSWORD,secret"`
+		Token    gonfiguration.Secret[string] `env:"TOKEN"`
+	}
+
+	src := Config{User: "app", Password: "hunter2", Token: gonfiguration.NewSecret("t0k3n")}
+
+	environ, err := gonfiguration.MarshalEnviron(src)
+	require.NoError(t, err)
+	require.Equal(t, []string{"DB_USER=app", "DB_PASSWORD=[REDACTED]", "TOKEN=[REDACTED]"}, environ)
+
+	environ, err = gonfiguration.MarshalEnviron(src, gonfiguration.WithSecretsRevealed())
+	require.NoError(t, err)
+	require.Equal(t, []string{"DB_USER=app", "DB_PASSWORD=hunter2", "TOKEN=t0k3n"}, environ)
+}
+
+func TestMarshalDotEnvJSONYAML(t *testing.T) {
+	defer gonfiguration.Reset()
+
+	type Database struct {
+		Host string `env:"HOST"`
+	}
+
+	type Config struct {
+		Port     int           `env:"PORT"`
+		Greeting string        `env:"GREETING"`
+		Timeout  time.Duration `env:"TIMEOUT"`
+		Hosts    []string      `env:"HOSTS"`
+		Empty    []int         `env:"EMPTY"`
+		Skipped  *string       `env:"SKIPPED"`
+		Database Database
+	}
+
+	src := Config{
+		Port:     8080,
+		Greeting: `hello "world" $USER`,
+		Timeout:  time.Second,
+		Hosts:    []string{"a", "b"},
+		Empty:    []int{},
+		Database: Database{Host: "db"},
+	}
+
+	opts := []gonfiguration.Option{gonfiguration.WithAutoKeys(), gonfiguration.WithPrefix("APP_")}
+
+	dotenv, err := gonfiguration.MarshalDotEnv(src, opts...)
+	require.NoError(t, err)
+	require.Equal(t, `APP_PORT=8080
+APP_GREETING="hello \"world\" \$USER"
+APP_TIMEOUT=1s
+APP_HOSTS=a,b
+APP_EMPTY=
+APP_DATABASE_HOST=db
+`, dotenv)
+
+	jsonOut, err := gonfiguration.MarshalJSON(src, opts...)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"APP_PORT": 8080,
+		"APP_GREETING": "hello \"world\" $USER",
+		"APP_TIMEOUT": "1s",
+		"APP_HOSTS": ["a", "b"],
+		"APP_EMPTY": [],
+		"APP_DATABASE_HOST": "db"
+	}`, string(jsonOut))
+	require.True(t, strings.HasPrefix(string(jsonOut), "{\n  \"APP_PORT\": 8080,"))
+
+	yamlOut, err := gonfiguration.MarshalYAML(src, opts...)
+	require.NoError(t, err)
+	require.Equal(t, `APP_PORT: 8080
+APP_GREETING: "hello \"world\" $USER"
+APP_TIMEOUT: "1s"
+APP_HOSTS:
+  - "a"
+  - "b"
+APP_EMPTY: []
+APP_DATABASE_HOST: "db"
+`, string(yamlOut))
+}
+
+func TestMarshalFloat32(t *testing.T) {
+	defer gonfiguration.Reset()
+
+	type Config struct {
+		Ratio  float32   `env:"RATIO"`
+		Ratios []float32 `env:"RATIOS"`
+	}
+
+	src := Config{Ratio: 0.1, Ratios: []float32{0.2, 1.5}}
+
+	environ, err := gonfiguration.MarshalEnviron(src)
+	require.NoError(t, err)
+	require.Equal(t, []string{"RATIO=0.1", "RATIOS=0.2,1.5"}, environ)
+
+	jsonOut, err := gonfiguration.MarshalJSON(src)
+	require.NoError(t, err)
+	require.Equal(t, "{\n  \"RATIO\": 0.1,\n  \"RATIOS\": [\n    0.2,\n    1.5\n  ]\n}\n", string(jsonOut))
+
+	yamlOut, err := gonfiguration.MarshalYAML(src)
+	require.NoError(t, err)
+	require.Contains(t, string(yamlOut), "RATIO: 0.1\n")
+
+	dst := Config{}
+	require.NoError(t, gonfiguration.Parse(&dst, gonfiguration.WithEnviron(environ)))
+	require.Equal(t, src, dst)
+}
+
+func TestMarshalErrors(t *testing.T) {
+	defer gonfiguration.Reset()
+
+	_, err := gonfiguration.MarshalEnviron(nil)
+	require.ErrorIs(t, err, gonfiguration.ErrDestinationNotStruct)
+
+	_, err = gonfiguration.MarshalEnviron((*struct{})(nil))
+	require.ErrorIs(t, err, gonfiguration.ErrNilDestination)
+
+	_, err = gonfiguration.MarshalJSON("nope")
+	require.ErrorIs(t, err, gonfiguration.ErrDestinationNotStruct)
+}
+
+func TestMarshalNamedStructMapCollision(t *testing.T) {
+	defer gonfiguration.Reset()
+
+	type Tenant struct {
+		DSN string `env:"DSN"`
+	}
+
+	type Config struct {
+		Tenants map[string]Tenant `env:"TENANT"`
+	}
+
+	src := Config{Tenants: map[string]Tenant{"acme": {DSN: "a"}, "ACME": {DSN: "b"}}}
+
+	_, err := gonfiguration.MarshalEnviron(src)
+	require.ErrorIs(t, err, gonfiguration.ErrDuplicateMapKey)
+	require.Contains(t, err.Error(), `"ACME" and "acme" both encode as ACME`)
+
+	type KeepCaseConfig struct {
+		Tenants map[string]Tenant `env:"TENANT,keepcase"`
+	}
+
+	environ, err := gonfiguration.MarshalEnviron(KeepCaseConfig(src))
+	require.NoError(t, err)
+	require.Equal(t, []string{"TENANT_ACME_DSN=b", "TENANT_acme_DSN=a"}, environ)
+}
+
+func TestMarshalSkipsCertPool(t *testing.T) {
+	defer gonfiguration.Reset()
+
+	type Config struct {
+		Port int            `env:"PORT"`
+		Pool *x509.CertPool `env:"CA"`
+	}
+
+	environ, err := gonfiguration.MarshalEnviron(Config{Port: 80, Pool: x509.NewCertPool()})
+	require.NoError(t, err)
+	require.Equal(t, []string{"PORT=80"}, environ)
+
+	yamlOut, err := gonfiguration.MarshalYAML(Config{Port: 80, Pool: x509.NewCertPool()})
+	require.NoError(t, err)
+	require.Equal(t, "PORT: 80\n", string(yamlOut))
+}
+
+func TestMarshalYAMLQuotesKeys(t *testing.T) {
+	defer gonfiguration.Reset()
+
+	type Config struct {
+		On    bool   `env:"ON"`
+		Num   int    `env:"1ST"`
+		Plain string `env:"PLAIN"`
+	}
+
+	out, err := gonfiguration.MarshalYAML(Config{On: true, Num: 1, Plain: "x"})
+	require.NoError(t, err)
+	require.Equal(t, "\"ON\": true\n\"1ST\": 1\nPLAIN: \"x\"\n", string(out))
+}
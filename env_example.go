@@ -0,0 +1,127 @@
+package gonfiguration
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/psyb0t/ctxerrors"
+)
+
+const (
+	// secretPlaceholder is written in place of secret values.
+	secretPlaceholder = "CHANGE_ME"
+
+	// exampleIndex and exampleName fill the <N> and <NAME> placeholders
+	// so example keys are real, usable keys.
+	exampleIndex = "0"
+	exampleName  = "EXAMPLE"
+
+	envExampleFileMode = 0o644
+)
+
+// EnvExample renders the docs as a .env.example file: each variable with
+// its description and type as comments, defaults filled in, required
+// variables left empty and marked, and secrets set to CHANGE_ME. Keys of
+// slices and maps of structs are written for element 0 and name EXAMPLE.
+func (d Docs) EnvExample() string {
+	var b strings.Builder
+
+	for i, doc := range d {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+
+		if doc.Description != "" {
+			fmt.Fprintf(&b, "# %s\n", doc.Description)
+		}
+
+		fmt.Fprintf(&b, "# %s\n", strings.Join(doc.exampleNotes(), ". ")+".")
+
+		fmt.Fprintf(&b, "%s=%s\n", doc.exampleKey(), dotenvValue(doc.exampleValue()))
+	}
+
+	return b.String()
+}
+
+// WriteEnvExample writes the .env.example of dst's type to path, for use
+// from a go:generate program.
+func WriteEnvExample(path string, dst any, opts ...Option) error {
+	docs, err := Describe(dst, opts...)
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(path, []byte(docs.EnvExample()), envExampleFileMode); err != nil {
+		return ctxerrors.Wrapf(err, "failed to write %s", path)
+	}
+
+	return nil
+}
+
+func (d VarDoc) exampleNotes() []string {
+	notes := []string{d.Type}
+
+	if d.Required {
+		notes = append(notes, "Required")
+	}
+
+	if d.Secret {
+		notes = append(notes, "Secret")
+	}
+
+	if len(d.Allowed) > 0 {
+		notes = append(notes, "One of: "+strings.Join(d.Allowed, ", "))
+	}
+
+	if len(d.Aliases) > 0 {
+		note := "Aliases: " + strings.Join(d.Aliases, ", ")
+		if d.Deprecated {
+			note += " (deprecated)"
+		}
+
+		notes = append(notes, note)
+	}
+
+	if strings.Contains(d.Key, indexPlaceholder) || strings.Contains(d.Key, namePlaceholder) {
+		notes = append(notes, "Pattern: "+d.Key)
+	}
+
+	return notes
+}
+
+// exampleKey fills the <N> and <NAME> placeholders of the key with
+// element 0 and name EXAMPLE.
+func (d VarDoc) exampleKey() string {
+	return strings.NewReplacer(indexPlaceholder, exampleIndex, namePlaceholder, exampleName).Replace(d.Key)
+}
+
+// exampleValue is what the example file sets the key to: blank for a
+// required key with nothing to fall back on, the placeholder for secrets
+// and the default otherwise.
+func (d VarDoc) exampleValue() string {
+	switch {
+	case d.Required && !d.HasDefault:
+		return ""
+	case d.Secret:
+		return secretPlaceholder
+	default:
+		return d.Default
+	}
+}
+
+// dotenvValue double-quotes val when it has anything a dotenv parser or
+// a shell sourcing the file would treat specially.
+func dotenvValue(val string) string {
+	if !strings.ContainsAny(val, " \t\n\"'`$\\#=;&|<>(){}*?!~") {
+		return val
+	}
+
+	return `"` + strings.NewReplacer(
+		`\`, `\\`,
+		`"`, `\"`,
+		"$", `\$`,
+		"`", "\\`",
+		"\n", `\n`,
+	).Replace(val) + `"`
+}
@@ -0,0 +1,81 @@
+package gonfiguration_test
+
+import (
+	"crypto/tls"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/psyb0t/gonfiguration"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDocsEnvExample(t *testing.T) {
+	defer gonfiguration.Reset()
+
+	type Tenant struct {
+		DSN string `env:"DSN"`
+	}
+
+	type Config struct {
+		Port     int                          `env:"PORT" default:"8080" desc:"Port to listen on."`
+		LogLevel string                       `env:"LOG_LEVEL" default:"info" oneof:"debug,info"`
+		DBURL    string                       `env:"DATABASE_URL,required,alias=DB_URL,deprecated" desc:"Postgres DSN."`
+		Token    gonfiguration.Secret[string] `env:"TOKEN" default:"dev-token"`
+		Greeting string                       `env:"GREETING" default:"hello \"world\" $USER"`
+		Region   string                       `env:"REGION,required" default:"eu-west-1"`
+		Cert     tls.Certificate              `env:"TLS_CERT,required" tlskey:"TLS_KEY"`
+		Tenants  map[string]Tenant            `env:"TENANT"`
+	}
+
+	docs, err := gonfiguration.Describe(Config{})
+	require.NoError(t, err)
+	require.Equal(t, `# Port to listen on.
+# int.
+PORT=8080
+
+# string. One of: debug, info.
+LOG_LEVEL=info
+
+# Postgres DSN.
+# string. Required. Aliases: DB_URL (deprecated).
+DATABASE_URL=
+
+# string. Secret.
+TOKEN=CHANGE_ME
+
+# string.
+GREETING="hello \"world\" \$USER"
+
+# string. Required.
+REGION=eu-west-1
+
+# tls.Certificate. Required.
+TLS_CERT=
+
+# Private key of TLS_CERT.
+# string. Required. Secret.
+TLS_KEY=
+
+# string. Pattern: TENANT_<NAME>_DSN.
+TENANT_EXAMPLE_DSN=
+`, docs.EnvExample())
+}
+
+func TestWriteEnvExample(t *testing.T) {
+	defer gonfiguration.Reset()
+
+	type Config struct {
+		Port int `env:"PORT" default:"8080"`
+	}
+
+	path := filepath.Join(t.TempDir(), ".env.example")
+	require.NoError(t, gonfiguration.WriteEnvExample(path, Config{}, gonfiguration.WithPrefix("APP_")))
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "# int.\nAPP_PORT=8080\n", string(content))
+
+	require.ErrorIs(t, gonfiguration.WriteEnvExample(path, "nope"), gonfiguration.ErrDestinationNotStruct)
+	require.Error(t, gonfiguration.WriteEnvExample(filepath.Join(path, "sub", "x"), Config{}))
+}
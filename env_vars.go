@@ -3,6 +3,7 @@ package gonfiguration
 import (
 	"maps"
 	"os"
+	"slices"
 	"strings"
 
 	"github.com/psyb0t/ctxerrors"
@@ -12,8 +13,18 @@ const (
 	envVarNumParts = 2
 )
 
+// GetEnvVars returns the env snapshot of the last Parse, with the values
+// of secret fields' keys masked.
 func GetEnvVars() map[string]string {
-	return gonfig.getEnvVars()
+	envVars := gonfig.getEnvVars()
+
+	for key := range envVars {
+		if gonfig.isSecret(key) {
+			envVars[key] = redacted
+		}
+	}
+
+	return envVars
 }
 
 func (g *gonfiguration) setEnvVar(key, val string) {
@@ -29,6 +40,43 @@ func (g *gonfiguration) setEnvVars(envVars map[string]string) {
 	}
 }
 
+// syncEnvVars caches the value of every key in keys, dropping the ones
+// that are no longer set so a variable removed between parses doesn't
+// linger. Cached keys under one of prefixes are replaced wholesale, since
+// an indexed slice or struct map that shrank doesn't look its old keys up.
+// Other keys outside keys are left alone.
+func (g *gonfiguration) syncEnvVars(
+	envVars map[string]string,
+	keys map[string]struct{},
+	prefixes []string,
+) {
+	g.Lock()
+	defer g.Unlock()
+
+	for key := range g.envVars {
+		if _, ok := keys[key]; ok {
+			continue
+		}
+
+		if slices.ContainsFunc(prefixes, func(prefix string) bool {
+			return strings.HasPrefix(key, prefix)
+		}) {
+			delete(g.envVars, key)
+		}
+	}
+
+	for key := range keys {
+		val, ok := envVars[key]
+		if !ok {
+			delete(g.envVars, key)
+
+			continue
+		}
+
+		g.envVars[key] = val
+	}
+}
+
 func (g *gonfiguration) getEnvVars() map[string]string {
 	g.RLock()
 	defer g.RUnlock()
@@ -40,8 +88,12 @@ func (g *gonfiguration) getEnvVars() map[string]string {
 }
 
 func getEnvVars() (map[string]string, error) {
+	return parseEnviron(os.Environ())
+}
+
+// parseEnviron turns KEY=VALUE strings into a map, later entries winning.
+func parseEnviron(rawVars []string) (map[string]string, error) {
 	envVars := map[string]string{}
-	rawVars := os.Environ()
 
 	for _, rawVar := range rawVars {
 		parts := strings.SplitN(rawVar, "=", envVarNumParts)
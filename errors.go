@@ -3,11 +3,28 @@ package gonfiguration
 import "errors"
 
 var (
-	ErrNilDestination       = errors.New("destination is nil")
-	ErrInvalidEnvVar        = errors.New("invalid environment variable")
-	ErrTargetNotPointer     = errors.New("destination must be a pointer")
-	ErrDestinationNotStruct = errors.New("destination must be a struct")
-	ErrUnsupportedFieldType = errors.New("unsupported field type")
-	ErrRequiredFieldNotSet  = errors.New("required field not set")
-	ErrDefaultTypeMismatch  = errors.New("default value type mismatch")
+	ErrNilDestination         = errors.New("destination is nil")
+	ErrInvalidEnvVar          = errors.New("invalid environment variable")
+	ErrTargetNotPointer       = errors.New("destination must be a pointer")
+	ErrDestinationNotStruct   = errors.New("destination must be a struct")
+	ErrUnsupportedFieldType   = errors.New("unsupported field type")
+	ErrRequiredFieldNotSet    = errors.New("required field not set")
+	ErrDefaultTypeMismatch    = errors.New("default value type mismatch")
+	ErrInvalidDuration        = errors.New("invalid duration")
+	ErrInvalidAddress         = errors.New("invalid address")
+	ErrInvalidURL             = errors.New("invalid url")
+	ErrURLSchemeNotAllowed    = errors.New("url scheme not allowed")
+	ErrUnknownEncoding        = errors.New("unknown encoding")
+	ErrLengthMismatch         = errors.New("length mismatch")
+	ErrInvalidCertificate     = errors.New("invalid certificate")
+	ErrCertificateExpired     = errors.New("certificate expired")
+	ErrCertificateNotYetValid = errors.New("certificate not yet valid")
+	ErrInvalidList            = errors.New("invalid list")
+	ErrInvalidJSON            = errors.New("invalid json")
+	ErrIndexGap               = errors.New("non-contiguous index")
+	ErrDuplicateMapKey        = errors.New("duplicate map key")
+	ErrConflictingAliases     = errors.New("conflicting values for aliased keys")
+	ErrAmbiguousKey           = errors.New("ambiguous key")
+	ErrUnknownKey             = errors.New("unknown key")
+	ErrDeprecatedKey          = errors.New("deprecated key")
 )
@@ -0,0 +1,151 @@
+package gonfiguration
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/psyb0t/ctxerrors"
+)
+
+// Source is the layer a field's value came from. Layers are listed from
+// lowest to highest priority; the environment is the only outside
+// source, there are no config files to point at.
+type Source string
+
+const (
+	SourceUnset      Source = "unset"
+	SourceTagDefault Source = "tag default"
+	SourceDefault    Source = "SetDefault"
+	SourceEnv        Source = "env"
+)
+
+// Candidate is one value a field could have taken from a given layer.
+// Key is the env key for SourceEnv and SourceDefault, empty otherwise.
+// Value is the input as written, or [REDACTED] for secret fields.
+type Candidate struct {
+	Source Source
+	Key    string
+	Value  string
+}
+
+// FieldReport tells where a field's value came from. Field is the Go
+// path, like "DB.Host" or "Upstreams[0].Host", and Key the env key the
+// field reads. The winning Candidate is embedded; its Key differs from
+// the field's Key when the value came from an alias. Overridden lists
+// the lower-priority layers that also had a value, highest first.
+type FieldReport struct {
+	Candidate
+
+	Field      string
+	Key        string
+	Secret     bool
+	Overridden []Candidate
+}
+
+// Report is the provenance of every field Explain parsed, in field order.
+type Report []FieldReport
+
+// Explain parses dst exactly like Parse does and reports where each field
+// got its value from. The report covers every field parsed before an
+// error, if there is one.
+func Explain(dst any, opts ...Option) (Report, error) {
+	report := Report{}
+
+	opts = append(opts, func(o *options) {
+		o.report = &report
+	})
+
+	if err := Parse(dst, opts...); err != nil {
+		return report, ctxerrors.Wrap(err, "failed to explain")
+	}
+
+	return report, nil
+}
+
+// String renders the report as an aligned table.
+func (r Report) String() string {
+	var b strings.Builder
+
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0) //nolint:mnd
+
+	_, _ = fmt.Fprintln(w, "FIELD\tKEY\tSOURCE\tVALUE\tOVERRIDDEN")
+
+	for _, field := range r {
+		source := string(field.Source)
+		if field.Candidate.Key != "" && field.Candidate.Key != field.Key {
+			source += " " + field.Candidate.Key
+		}
+
+		overridden := make([]string, 0, len(field.Overridden))
+		for _, candidate := range field.Overridden {
+			overridden = append(overridden, fmt.Sprintf("%s=%q", candidate.Source, candidate.Value))
+		}
+
+		_, _ = fmt.Fprintf(
+			w, "%s\t%s\t%s\t%q\t%s\n",
+			field.Field, field.Key, source, field.Value, strings.Join(overridden, ", "),
+		)
+	}
+
+	_ = w.Flush()
+
+	return b.String()
+}
+
+// explainField records the report entry of a field that was just filled
+// from source, when the parse is being explained.
+func (p *parser) explainField(
+	path, key string,
+	tag envTag,
+	source Source,
+	found envLookup,
+	tagDefault *string,
+) {
+	if p.options.report == nil {
+		return
+	}
+
+	mask := func(val string) string {
+		if tag.secret {
+			return redacted
+		}
+
+		return val
+	}
+
+	candidates := []Candidate{}
+
+	if found.val != nil {
+		candidates = append(candidates, Candidate{Source: SourceEnv, Key: found.key, Value: mask(*found.val)})
+
+		for _, shadowed := range found.shadowed {
+			candidates = append(candidates, Candidate{Source: SourceEnv, Key: shadowed, Value: mask(*found.val)})
+		}
+	}
+
+	if defaultValue := gonfig.getDefault(key); defaultValue != nil {
+		candidates = append(candidates, Candidate{
+			Source: SourceDefault,
+			Key:    key,
+			Value:  mask(fmt.Sprint(defaultValue)),
+		})
+	}
+
+	if tagDefault != nil {
+		candidates = append(candidates, Candidate{Source: SourceTagDefault, Value: mask(*tagDefault)})
+	}
+
+	field := FieldReport{Field: path, Key: key, Secret: tag.secret}
+	field.Candidate = Candidate{Source: source}
+
+	if source != SourceUnset {
+		field.Candidate = candidates[0]
+	}
+
+	if len(candidates) > 1 {
+		field.Overridden = candidates[1:]
+	}
+
+	*p.options.report = append(*p.options.report, field)
+}
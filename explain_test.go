@@ -0,0 +1,138 @@
+package gonfiguration_test
+
+import (
+	"testing"
+
+	"github.com/psyb0t/gonfiguration"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExplain(t *testing.T) {
+	type Upstream struct {
+		Host string `env:"HOST"`
+	}
+
+	type Config struct {
+		Port      int        `env:"PORT" default:"80"`
+		Workers   int        `env:"WORKERS" default:"1"`
+		LogLevel  string     `env:"LOG_LEVEL" default:"info"`
+		DBURL     string     `env:"DATABASE_URL,alias=DB_URL"`
+		Password  string     `env:"DB_PASS,secret" default:"changeme"`
+		Name      string     `env:"NAME"`
+		Upstreams []Upstream `env:"UPSTREAM"`
+	}
+
+	t.Run("winning layer and overridden candidates", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		gonfiguration.SetDefault("PORT", 8000)
+		gonfiguration.SetDefault("WORKERS", 4)
+		t.Setenv("PORT", "9000")
+		t.Setenv("DB_URL", "postgres://old")
+		t.Setenv("DB_PASS", "hunter2")
+		t.Setenv("UPSTREAM_0_HOST", "a.internal")
+
+		cfg := Config{}
+		report, err := gonfiguration.Explain(&cfg)
+		require.NoError(t, err)
+		require.Equal(t, 9000, cfg.Port)
+
+		require.Equal(t, gonfiguration.Report{
+			{
+				Field:     "Port",
+				Key:       "PORT",
+				Candidate: gonfiguration.Candidate{Source: gonfiguration.SourceEnv, Key: "PORT", Value: "9000"},
+				Overridden: []gonfiguration.Candidate{
+					{Source: gonfiguration.SourceDefault, Key: "PORT", Value: "8000"},
+					{Source: gonfiguration.SourceTagDefault, Value: "80"},
+				},
+			},
+			{
+				Field:     "Workers",
+				Key:       "WORKERS",
+				Candidate: gonfiguration.Candidate{Source: gonfiguration.SourceDefault, Key: "WORKERS", Value: "4"},
+				Overridden: []gonfiguration.Candidate{
+					{Source: gonfiguration.SourceTagDefault, Value: "1"},
+				},
+			},
+			{
+				Field:     "LogLevel",
+				Key:       "LOG_LEVEL",
+				Candidate: gonfiguration.Candidate{Source: gonfiguration.SourceTagDefault, Value: "info"},
+			},
+			{
+				Field:     "DBURL",
+				Key:       "DATABASE_URL",
+				Candidate: gonfiguration.Candidate{Source: gonfiguration.SourceEnv, Key: "DB_URL", Value: "postgres://old"},
+			},
+			{
+				Field:     "Password",
+				Key:       "DB_PASS",
+				Secret:    true,
+				Candidate: gonfiguration.Candidate{Source: gonfiguration.SourceEnv, Key: "DB_PASS", Value: "[REDACTED]"},
+				Overridden: []gonfiguration.Candidate{
+					{Source: gonfiguration.SourceTagDefault, Value: "[REDACTED]"},
+				},
+			},
+			{
+				Field:     "Name",
+				Key:       "NAME",
+				Candidate: gonfiguration.Candidate{Source: gonfiguration.SourceUnset},
+			},
+			{
+				Field:     "Upstreams[0].Host",
+				Key:       "UPSTREAM_0_HOST",
+				Candidate: gonfiguration.Candidate{Source: gonfiguration.SourceEnv, Key: "UPSTREAM_0_HOST", Value: "a.internal"},
+			},
+		}, report)
+
+		table := report.String()
+		require.Contains(t, table, "FIELD")
+		require.Contains(t, table, "env DB_URL")
+		require.NotContains(t, table, "hunter2")
+		require.NotContains(t, table, "changeme")
+	})
+
+	t.Run("nested and map paths", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		type Database struct {
+			Host string
+		}
+
+		type Tenant struct {
+			DSN string `env:"DSN"`
+		}
+
+		type NestedConfig struct {
+			DB      Database
+			Tenants map[string]Tenant `env:"TENANT"`
+		}
+
+		t.Setenv("DB_HOST", "db.internal")
+		t.Setenv("TENANT_ACME_DSN", "postgres://acme")
+
+		report, err := gonfiguration.Explain(&NestedConfig{}, gonfiguration.WithAutoKeys())
+		require.NoError(t, err)
+		require.Len(t, report, 2)
+		require.Equal(t, "DB.Host", report[0].Field)
+		require.Equal(t, `Tenants["acme"].DSN`, report[1].Field)
+	})
+
+	t.Run("partial report on error", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		type FailingConfig struct {
+			Port int `env:"PORT"`
+			Name int `env:"NAME"`
+		}
+
+		t.Setenv("PORT", "80")
+		t.Setenv("NAME", "not a number")
+
+		report, err := gonfiguration.Explain(&FailingConfig{})
+		require.Error(t, err)
+		require.Len(t, report, 1)
+		require.Equal(t, "Port", report[0].Field)
+	})
+}
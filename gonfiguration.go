@@ -1,11 +1,20 @@
 package gonfiguration
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"maps"
+	"net"
+	"net/mail"
+	"net/netip"
+	"net/url"
 	"reflect"
+	"regexp"
 	"strconv"
 	"strings"
 	"sync"
+	"text/template"
 	"time"
 
 	"github.com/psyb0t/ctxerrors"
@@ -21,34 +30,51 @@ var (
 func init() {
 	gonfigOnce.Do(func() {
 		gonfig = &gonfiguration{
-			defaults: map[string]any{},
-			envVars:  map[string]string{},
+			defaults:   map[string]any{},
+			envVars:    map[string]string{},
+			secretKeys: map[string]struct{}{},
 		}
 	})
 }
 
-func Parse(dst any) error {
-	envVars, err := getEnvVars()
+func Parse(dst any, opts ...Option) error {
+	envVars, err := newOptions(opts).envVars()
 	if err != nil {
 		return ctxerrors.Wrap(err, "failed to get env vars")
 	}
 
-	gonfig.setEnvVars(envVars)
-
 	dstVal, err := getDstStructValue(dst)
 	if err != nil {
 		return ctxerrors.Wrap(err, "invalid destination")
 	}
 
-	if err := parseDstFields(dstVal, envVars); err != nil {
+	p := newParser(envVars, opts)
+	p.root = dstVal.Type()
+
+	if !p.options.consumedOnly {
+		gonfig.setEnvVars(envVars)
+	}
+
+	err = p.parseFields(dstVal, p.options.prefix, "")
+	gonfig.addSecretKeys(p.secretKeys)
+
+	if p.options.consumedOnly {
+		gonfig.syncEnvVars(p.envVars, p.known, p.ownedPrefixes)
+	}
+
+	if err != nil {
 		return ctxerrors.Wrap(err, "failed to parse fields")
 	}
 
+	if err := p.checkUnknownKeys(); err != nil {
+		return ctxerrors.Wrap(err, "strict mode")
+	}
+
 	return nil
 }
 
-func MustParse(dst any) {
-	if err := Parse(dst); err != nil {
+func MustParse(dst any, opts ...Option) {
+	if err := Parse(dst, opts...); err != nil {
 		panic(err)
 	}
 }
@@ -65,6 +91,12 @@ func GetAllValues() map[string]any {
 		allValues[key] = val
 	}
 
+	for key := range allValues {
+		if gonfig.isSecret(key) {
+			allValues[key] = redacted
+		}
+	}
+
 	return allValues
 }
 
@@ -74,8 +106,9 @@ func Reset() {
 
 type gonfiguration struct {
 	sync.RWMutex
-	defaults map[string]any
-	envVars  map[string]string
+	defaults   map[string]any
+	envVars    map[string]string
+	secretKeys map[string]struct{}
 }
 
 func (g *gonfiguration) reset() {
@@ -83,50 +116,286 @@ func (g *gonfiguration) reset() {
 	defer g.Unlock()
 
 	gonfig = &gonfiguration{
-		defaults: map[string]any{},
-		envVars:  map[string]string{},
+		defaults:   map[string]any{},
+		envVars:    map[string]string{},
+		secretKeys: map[string]struct{}{},
 	}
 }
 
-func parseDstFields(
-	dstVal reflect.Value,
-	envVars map[string]string,
-) error {
+// parser holds the env snapshot and options of a single Parse call.
+type parser struct {
+	envVars       map[string]string
+	options       options
+	foldConflicts map[string][]string
+	// known holds every env key a field looked up, set or not.
+	known map[string]struct{}
+	// ownedPrefixes holds the key prefixes of indexed slice and struct map
+	// fields, whose set of keys can shrink between parses.
+	ownedPrefixes []string
+	// secretKeys holds the keys of secret fields, for gonfig to mask.
+	secretKeys []string
+	// check collects field problems for Check instead of failing on the
+	// first one.
+	check *CheckReport
+	// root is the destination's struct type, walked again to find every
+	// key it reads when a required key is missing.
+	root reflect.Type
+	// readKeys caches the keys of that walk. probing marks the walk's own
+	// parser, which doesn't suggest keys.
+	readKeys map[string]struct{}
+	probing  bool
+	// describing holds the struct types on the path Describe is walking,
+	// to catch types that contain themselves.
+	describing map[reflect.Type]bool
+}
+
+// parseFields fills the fields of dstVal. prefix is prepended to every
+// key: the WithPrefix prefix plus, for nested structs and the elements of
+// indexed slices and named maps, the keys leading to them. path is the
+// matching Go expression, such as "Upstreams[0].", for Explain.
+func (p *parser) parseFields(dstVal reflect.Value, prefix, path string) error {
 	for i := range dstVal.NumField() {
 		fieldType := dstVal.Type().Field(i)
 
-		tag, ok := fieldType.Tag.Lookup("env")
+		envTag, ok := p.fieldTag(fieldType)
 		if !ok {
 			continue
 		}
 
-		key, required := parseTag(tag)
+		key := prefix + envTag.key
+		fieldValue := dstVal.Field(i)
+
+		if inner, ok := unwrapSecret(fieldValue); ok {
+			fieldValue = inner
+			envTag.secret = true
+		}
+
+		if envTag.secret {
+			p.secretKeys = append(p.secretKeys, secretKeys(prefix, envTag)...)
+		}
+
+		if !envTag.json && isIndexedStructSlice(fieldValue.Type()) {
+			if err := p.fillIndexedSlice(fieldValue, key, path+fieldType.Name, envTag); err != nil {
+				return p.fieldError(key, err)
+			}
+
+			continue
+		}
+
+		if !envTag.json && isNamedStructMap(fieldValue.Type()) {
+			if err := p.fillNamedStructMap(fieldValue, key, path+fieldType.Name, envTag); err != nil {
+				return p.fieldError(key, err)
+			}
+
+			continue
+		}
+
+		if !envTag.json && p.options.naming != nil && isNestedStruct(fieldValue.Type()) {
+			nestedPath := path + fieldType.Name + "."
+			if fieldType.Anonymous {
+				nestedPath = path
+			}
+
+			if err := p.parseFields(fieldValue, p.nestedPrefix(prefix, envTag), nestedPath); err != nil {
+				return err
+			}
+
+			continue
+		}
+
 		tagDefault := tagDefaultFromField(fieldType)
+		format := valueFormatFromField(fieldType)
+		format.json = envTag.json
 
-		fieldValue := dstVal.Field(i)
-		if !isSupportedType(fieldValue) {
+		if fieldValue.Type() == reflect.TypeFor[tls.Certificate]() {
+			p.secretKeys = append(p.secretKeys, certificateKeys(prefix, envTag, format)...)
+
+			// Without tlskey the private key sits in the field's own PEM
+			envTag.secret = envTag.secret || format.tlsKeyEnv == ""
+		}
+
+		if format.tlsKeyEnv != "" {
+			tlsKey, hasTLSKey, err := p.get(prefix + format.tlsKeyEnv)
+			if err != nil {
+				if err := p.fieldError(key, err); err != nil {
+					return err
+				}
+
+				continue
+			}
+
+			format.tlsKey, format.hasTLSKey = tlsKey, hasTLSKey
+		}
+
+		if !envTag.json && !isSupportedType(fieldValue) {
 			return ErrUnsupportedFieldType
 		}
 
-		if err := fillFieldValue(fieldValue, key, required, envVars, tagDefault); err != nil {
-			return ctxerrors.Wrap(err, "failed to set field value")
+		found, err := p.lookup(prefix, envTag)
+		if err != nil {
+			if err := p.fieldError(key, err); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		source, err := p.fillFieldValue(fieldValue, key, envTag.required, found.val, tagDefault, format)
+		if err != nil && envTag.secret && !errors.Is(err, ErrRequiredFieldNotSet) {
+			err = &redactedError{key: key, err: err}
+		}
+
+		if err != nil {
+			if err := p.fieldError(key, ctxerrors.Wrap(err, "failed to set field value")); err != nil {
+				return err
+			}
+
+			continue
 		}
+
+		p.explainField(path+fieldType.Name, key, envTag, source, found, tagDefault)
 	}
 
 	return nil
 }
 
-func parseTag(tag string) (string, bool) {
+// fieldTag returns the parsed env tag of field and whether the field is
+// parsed at all. Without a naming strategy only fields with an env tag
+// are; with one, every exported field is and a missing key is derived
+// from the field name. env:"-" always excludes a field.
+func (p *parser) fieldTag(field reflect.StructField) (envTag, bool) {
+	tag, hasTag := field.Tag.Lookup("env")
+	if tag == "-" {
+		return envTag{}, false
+	}
+
+	if p.options.naming == nil {
+		return parseTag(tag), hasTag
+	}
+
+	if !field.IsExported() {
+		return envTag{}, false
+	}
+
+	parsed := parseTag(tag)
+	if parsed.key == "" && !field.Anonymous {
+		parsed.key = p.options.naming.FieldKey(field.Name)
+	}
+
+	return parsed, true
+}
+
+// envLookup is where a field's env value was found: the key that won and
+// any lower-precedence aliases set to the same value.
+type envLookup struct {
+	key      string
+	val      *string
+	shadowed []string
+}
+
+// lookup returns the env value of a field, with a nil val if it isn't
+// set. The field's key is tried first, then its aliases in tag order.
+// Setting more than one of them to different values is an error, and a
+// value coming from an alias of a deprecated field is logged as a warning.
+func (p *parser) lookup(prefix string, tag envTag) (envLookup, error) {
+	found := envLookup{}
+
+	for _, name := range append([]string{tag.key}, tag.aliases...) {
+		key := prefix + name
+
+		val, ok, err := p.get(key)
+		if err != nil {
+			return envLookup{}, err
+		}
+
+		if !ok {
+			continue
+		}
+
+		if found.val == nil {
+			found.key, found.val = key, &val
+
+			continue
+		}
+
+		if val != *found.val {
+			return envLookup{}, ctxerrors.Wrapf(
+				ErrConflictingAliases,
+				"field %s: %s and %s are set to different values", prefix+tag.key, found.key, key,
+			)
+		}
+
+		found.shadowed = append(found.shadowed, key)
+	}
+
+	if found.val != nil && tag.deprecated && found.key != prefix+tag.key {
+		p.warnDeprecated(found.key, prefix+tag.key)
+	}
+
+	return found, nil
+}
+
+// nestedPrefix returns the key prefix for the fields of a nested struct.
+// Embedded structs without a key of their own are flattened into the
+// parent.
+func (p *parser) nestedPrefix(prefix string, tag envTag) string {
+	if tag.key == "" {
+		return prefix
+	}
+
+	return prefix + tag.key + p.options.naming.Separator
+}
+
+// isNestedStruct reports whether typ is a plain struct whose fields are
+// parsed on their own rather than from a single value.
+func isNestedStruct(typ reflect.Type) bool {
+	_, hasSetter := typeSetters[typ]
+
+	return typ.Kind() == reflect.Struct && !hasSetter
+}
+
+// envTag is a parsed `env` struct tag: the key followed by options.
+type envTag struct {
+	key        string
+	aliases    []string
+	required   bool
+	json       bool
+	sparse     bool
+	keepCase   bool
+	deprecated bool
+	secret     bool
+}
+
+func parseTag(tag string) envTag {
 	parts := strings.Split(tag, ",")
-	key := strings.TrimSpace(parts[0])
+	parsed := envTag{key: strings.TrimSpace(parts[0])}
 
 	for _, part := range parts[1:] {
-		if strings.TrimSpace(part) == "required" {
-			return key, true
+		option := strings.TrimSpace(part)
+
+		if alias, ok := strings.CutPrefix(option, "alias="); ok {
+			parsed.aliases = append(parsed.aliases, strings.TrimSpace(alias))
+
+			continue
+		}
+
+		switch option {
+		case "required":
+			parsed.required = true
+		case "json":
+			parsed.json = true
+		case "sparse":
+			parsed.sparse = true
+		case "keepcase":
+			parsed.keepCase = true
+		case "deprecated":
+			parsed.deprecated = true
+		case "secret":
+			parsed.secret = true
 		}
 	}
 
-	return key, false
+	return parsed
 }
 
 func tagDefaultFromField(field reflect.StructField) *string {
@@ -138,41 +407,49 @@ func tagDefaultFromField(field reflect.StructField) *string {
 	return &val
 }
 
-func fillFieldValue(
+func (p *parser) fillFieldValue(
 	fieldValue reflect.Value,
 	key string,
 	required bool,
-	envVars map[string]string,
+	envVal *string,
 	tagDefault *string,
-) error {
+	format valueFormat,
+) (Source, error) {
+	source := SourceUnset
+
 	// Tag default has lowest priority
 	if tagDefault != nil {
-		if err := setEnvVarValue(fieldValue, *tagDefault); err != nil {
-			return ctxerrors.Wrapf(err, "field %s: invalid default tag value %q", key, *tagDefault)
+		if err := setEnvVarValue(fieldValue, *tagDefault, format); err != nil {
+			return source, ctxerrors.Wrapf(err, "field %s: invalid default tag value %q", key, *tagDefault)
 		}
+
+		source = SourceTagDefault
 	}
 
 	// Programmatic default overrides tag default
 	hasDefault, err := setDefaultValue(fieldValue, key)
 	if err != nil {
-		return err
+		return source, err
 	}
 
-	if !hasDefault {
-		hasDefault = tagDefault != nil
+	if hasDefault {
+		source = SourceDefault
 	}
 
 	// Env var has highest priority
-	envVal, hasEnvVar := envVars[key]
-	if !hasEnvVar {
-		if required && !hasDefault {
-			return ctxerrors.Wrapf(ErrRequiredFieldNotSet, "field %s", key)
+	if envVal == nil {
+		if required && source == SourceUnset {
+			return source, p.requiredFieldError(key)
 		}
 
-		return nil
+		return source, nil
+	}
+
+	if err := setEnvVarValue(fieldValue, *envVal, format); err != nil {
+		return source, ctxerrors.Wrapf(err, "field %s", key)
 	}
 
-	return setEnvVarValue(fieldValue, envVal)
+	return SourceEnv, nil
 }
 
 func setDefaultValue(
@@ -193,21 +470,57 @@ func setDefaultValue(
 	return true, nil
 }
 
+// setDefaultOrRequire handles a field with no env value at all: it gets
+// its programmatic default if there is one and fails if it's required.
+func (p *parser) setDefaultOrRequire(
+	fieldValue reflect.Value,
+	key, path string,
+	tag envTag,
+) error {
+	hasDefault, err := setDefaultValue(fieldValue, key)
+	if err != nil {
+		return err
+	}
+
+	if tag.required && !hasDefault {
+		return p.requiredFieldError(key)
+	}
+
+	source := SourceUnset
+	if hasDefault {
+		source = SourceDefault
+	}
+
+	p.explainField(path, key, tag, source, envLookup{}, nil)
+
+	return nil
+}
+
 func setEnvVarValue(
 	fieldValue reflect.Value,
 	envVal string,
+	format valueFormat,
 ) error {
-	// Handle time.Duration specifically since it has underlying type int64
-	if fieldValue.Type() == reflect.TypeFor[time.Duration]() {
-		return setDuration(fieldValue, envVal)
+	if format.json {
+		return setJSON(fieldValue, envVal)
 	}
 
-	// Handle []string specifically
-	if fieldValue.Type() == reflect.TypeFor[[]string]() {
-		return setStringSlice(fieldValue, envVal)
+	// Types with dedicated setters win over the generic kind switch,
+	// e.g. time.Duration which has underlying type int64
+	if setter, ok := typeSetters[fieldValue.Type()]; ok {
+		return setter(fieldValue, envVal, format)
+	}
+
+	// []byte and [N]byte hold encoded binary, not a list of numbers
+	if isByteSequence(fieldValue.Type()) {
+		return setBytes(fieldValue, envVal, format)
 	}
 
 	switch fieldValue.Kind() { //nolint:exhaustive
+	case reflect.Slice:
+		return setSlice(fieldValue, envVal, format)
+	case reflect.Array:
+		return setArray(fieldValue, envVal, format)
 	case reflect.String:
 		fieldValue.SetString(envVal)
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
@@ -285,38 +598,82 @@ func setBool(
 	return nil
 }
 
-func setDuration(
-	fieldValue reflect.Value,
-	envVal string,
-) error {
-	d, err := time.ParseDuration(envVal)
-	if err != nil {
-		return ctxerrors.Wrap(err, "failed to parse duration")
-	}
+// valueSetter parses envVal into fieldValue for types that need more
+// than the generic kind-based handling.
+type valueSetter func(fieldValue reflect.Value, envVal string, format valueFormat) error
 
-	fieldValue.Set(reflect.ValueOf(d))
+// typeSetters maps types that need more than the generic kind-based
+// handling to their setters.
+//
+//nolint:gochecknoglobals
+var typeSetters = map[reflect.Type]valueSetter{
+	reflect.TypeFor[time.Duration]():      setDuration,
+	reflect.TypeFor[time.Time]():          setTime,
+	reflect.TypeFor[*time.Location]():     setLocation,
+	reflect.TypeFor[url.URL]():            setURL,
+	reflect.TypeFor[*url.URL]():           setURL,
+	reflect.TypeFor[net.IP]():             setIP,
+	reflect.TypeFor[net.IPNet]():          setIPNet,
+	reflect.TypeFor[*net.IPNet]():         setIPNet,
+	reflect.TypeFor[netip.Addr]():         setNetipAddr,
+	reflect.TypeFor[netip.AddrPort]():     setNetipAddrPort,
+	reflect.TypeFor[netip.Prefix]():       setNetipPrefix,
+	reflect.TypeFor[mail.Address]():       setMailAddress,
+	reflect.TypeFor[*mail.Address]():      setMailAddress,
+	reflect.TypeFor[*regexp.Regexp]():     setRegexp,
+	reflect.TypeFor[*template.Template](): setTemplate,
+	reflect.TypeFor[tls.Certificate]():    setCertificate,
+	reflect.TypeFor[*x509.CertPool]():     setCertPool,
+}
 
-	return nil
+// valueFormat holds the per-field tags that change how a raw value
+// is parsed, as opposed to where it comes from.
+type valueFormat struct {
+	layout           string
+	extendedDuration bool
+	schemes          []string
+	encoding         string
+	tlsKeyEnv        string
+	tlsKey           string
+	hasTLSKey        bool
+	sep              string
+	dropEmpty        bool
+	dedupe           bool
+	json             bool
+	oneOf            []string
 }
 
-func setStringSlice(
-	fieldValue reflect.Value,
-	envVal string,
-) error {
-	if envVal == "" {
-		fieldValue.Set(reflect.ValueOf([]string{}))
+func valueFormatFromField(field reflect.StructField) valueFormat {
+	format := valueFormat{
+		layout:           field.Tag.Get("layout"),
+		extendedDuration: field.Tag.Get("duration") == durationFormatExtended,
+		encoding:         field.Tag.Get("encoding"),
+		tlsKeyEnv:        field.Tag.Get("tlskey"),
+		sep:              field.Tag.Get("sep"),
+	}
 
-		return nil
+	for option := range strings.SplitSeq(field.Tag.Get("list"), ",") {
+		switch strings.TrimSpace(option) {
+		case listOptionDropEmpty:
+			format.dropEmpty = true
+		case listOptionDedupe:
+			format.dedupe = true
+		}
 	}
 
-	parts := strings.Split(envVal, ",")
-	for i, part := range parts {
-		parts[i] = strings.TrimSpace(part)
+	if oneOf, ok := field.Tag.Lookup("oneof"); ok {
+		for val := range strings.SplitSeq(oneOf, ",") {
+			format.oneOf = append(format.oneOf, strings.TrimSpace(val))
+		}
 	}
 
-	fieldValue.Set(reflect.ValueOf(parts))
+	if schemes := field.Tag.Get("scheme"); schemes != "" {
+		for scheme := range strings.SplitSeq(schemes, ",") {
+			format.schemes = append(format.schemes, strings.ToLower(strings.TrimSpace(scheme)))
+		}
+	}
 
-	return nil
+	return format
 }
 
 func getDstStructValue(dst any) (reflect.Value, error) {
@@ -338,17 +695,23 @@ func getDstStructValue(dst any) (reflect.Value, error) {
 }
 
 func isSupportedType(fieldValue reflect.Value) bool {
-	// Handle time.Duration specifically
-	if fieldValue.Type() == reflect.TypeFor[time.Duration]() {
+	return isSupportedValueType(fieldValue.Type())
+}
+
+func isSupportedValueType(typ reflect.Type) bool {
+	if _, ok := typeSetters[typ]; ok {
 		return true
 	}
 
-	// Handle []string specifically
-	if fieldValue.Type() == reflect.TypeFor[[]string]() {
+	if isByteSequence(typ) {
 		return true
 	}
 
-	switch fieldValue.Kind() { //nolint:exhaustive
+	if isListKind(typ.Kind()) {
+		return isSupportedListElem(typ.Elem())
+	}
+
+	switch typ.Kind() { //nolint:exhaustive
 	case reflect.String,
 		reflect.Int,
 		reflect.Int8,
@@ -1,6 +1,9 @@
 package gonfiguration
 
 import (
+	"net"
+	"net/netip"
+	"net/url"
 	"reflect"
 	"testing"
 	"time"
@@ -21,7 +24,8 @@ func TestParseDstFields(t *testing.T) {
 		}
 
 		dst := EnvTestStruct{}
-		err := parseDstFields(reflect.ValueOf(&dst).Elem(), envVars)
+		p := newParser(envVars, nil)
+		err := p.parseFields(reflect.ValueOf(&dst).Elem(), "", "")
 
 		require.NoError(t, err)
 		require.Equal(t, "test", dst.StringField)
@@ -34,7 +38,8 @@ func TestParseDstFields(t *testing.T) {
 		}
 
 		dst := EnvTestStruct{}
-		err := parseDstFields(reflect.ValueOf(&dst).Elem(), envVars)
+		p := newParser(envVars, nil)
+		err := p.parseFields(reflect.ValueOf(&dst).Elem(), "", "")
 
 		require.Error(t, err)
 	})
@@ -50,7 +55,8 @@ func TestUnsupportedFieldType(t *testing.T) {
 	}
 
 	dst := UnsupportedStruct{}
-	err := parseDstFields(reflect.ValueOf(&dst).Elem(), envVars)
+	p := newParser(envVars, nil)
+	err := p.parseFields(reflect.ValueOf(&dst).Elem(), "", "")
 
 	require.Error(t, err)
 	require.ErrorIs(t, err, ErrUnsupportedFieldType)
@@ -60,7 +66,7 @@ func TestSetEnvVarValueUnsupportedType(t *testing.T) {
 	envVal := "test_value"
 
 	mapValue := reflect.ValueOf(make(map[string]string))
-	err := setEnvVarValue(mapValue, envVal)
+	err := setEnvVarValue(mapValue, envVal, valueFormat{})
 
 	require.Error(t, err)
 	require.ErrorIs(t, err, ErrUnsupportedFieldType)
@@ -83,7 +89,21 @@ func TestIsSupportedType(t *testing.T) {
 		reflect.ValueOf(float64(0)),
 		reflect.ValueOf(false),
 		reflect.ValueOf(time.Duration(0)),
+		reflect.ValueOf(time.Time{}),
+		reflect.ValueOf(time.UTC),
 		reflect.ValueOf([]string{}),
+		reflect.ValueOf([]int{}),
+		reflect.ValueOf(url.URL{}),
+		reflect.ValueOf(&url.URL{}),
+		reflect.ValueOf(net.IP{}),
+		reflect.ValueOf([]net.IP{}),
+		reflect.ValueOf(netip.Addr{}),
+		reflect.ValueOf([]netip.Prefix{}),
+		reflect.ValueOf([]byte{}),
+		reflect.ValueOf([32]byte{}),
+		reflect.ValueOf([1]string{}),
+		reflect.ValueOf([2]float64{}),
+		reflect.ValueOf([][]byte{}),
 	}
 
 	for _, val := range supportedTypes {
@@ -92,7 +112,8 @@ func TestIsSupportedType(t *testing.T) {
 
 	unsupportedTypes := []reflect.Value{
 		reflect.ValueOf(make(map[string]string)),
-		reflect.ValueOf([1]string{}),
+		reflect.ValueOf([][]string{}),
+		reflect.ValueOf([2][2]int{}),
 		reflect.ValueOf(struct{}{}),
 		reflect.ValueOf(&struct{}{}),
 		reflect.ValueOf(make(chan int)),
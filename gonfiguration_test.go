@@ -158,9 +158,15 @@ func TestParse(t *testing.T) {
 			expectError: true,
 		},
 		{
-			name:        "config with time field",
-			input:       &TimeFieldConfig{},
-			expectError: true,
+			name:  "config with time field",
+			input: &TimeFieldConfig{},
+			envConfig: map[string]any{
+				"BIRTHDAY": "1990-05-17T08:30:00Z",
+			},
+			expected: &TimeFieldConfig{
+				Birthday: time.Date(1990, time.May, 17, 8, 30, 0, 0, time.UTC),
+			},
+			expectError: false,
 		},
 		{
 			name:        "non-pointer dest",
@@ -0,0 +1,104 @@
+package gonfiguration
+
+import (
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/psyb0t/ctxerrors"
+)
+
+// indexSeparator joins a slice field's key, the element index and the
+// element's own keys, as in UPSTREAM_0_HOST.
+const indexSeparator = "_"
+
+// isIndexedStructSlice reports whether typ is a slice of plain structs,
+// which is filled from indexed keys rather than a delimited list.
+func isIndexedStructSlice(typ reflect.Type) bool {
+	if typ.Kind() != reflect.Slice {
+		return false
+	}
+
+	return isNestedStruct(typ.Elem())
+}
+
+// fillIndexedSlice fills a []Struct field from keys like KEY_0_HOST and
+// KEY_1_HOST, one element per index found in the env snapshot. Indices have to
+// run from 0 without gaps unless the tag has the sparse option, in which
+// case the elements are packed in index order.
+func (p *parser) fillIndexedSlice(
+	fieldValue reflect.Value,
+	key, path string,
+	tag envTag,
+) error {
+	p.ownedPrefixes = append(p.ownedPrefixes, p.envKey(key+indexSeparator))
+	indices := discoverIndices(p.envVars, p.envKey(key+indexSeparator))
+
+	if len(indices) == 0 {
+		return p.setDefaultOrRequire(fieldValue, key, path, tag)
+	}
+
+	if !tag.sparse {
+		for i, index := range indices {
+			if i != index {
+				return ctxerrors.Wrapf(
+					ErrIndexGap,
+					"field %s: missing index %d, found %v", key, i, indices,
+				)
+			}
+		}
+	}
+
+	slice := reflect.MakeSlice(fieldValue.Type(), len(indices), len(indices))
+
+	for i, index := range indices {
+		elemPrefix := key + indexSeparator + strconv.Itoa(index) + indexSeparator
+		elemPath := path + "[" + strconv.Itoa(i) + "]."
+
+		if err := p.parseFields(slice.Index(i), elemPrefix, elemPath); err != nil {
+			return ctxerrors.Wrapf(err, "field %s index %d", key, index)
+		}
+	}
+
+	fieldValue.Set(slice)
+
+	return nil
+}
+
+// discoverIndices returns the sorted distinct N of every PREFIX<N>_...
+// key in envVars. Indices with leading zeros are ignored so 1 and 01
+// can't both claim the same element.
+func discoverIndices(envVars map[string]string, prefix string) []int {
+	seen := map[int]struct{}{}
+
+	for envKey := range envVars {
+		rest, ok := strings.CutPrefix(envKey, prefix)
+		if !ok {
+			continue
+		}
+
+		digits, _, found := strings.Cut(rest, indexSeparator)
+		if !found || !isCanonicalIndex(digits) {
+			continue
+		}
+
+		index, err := strconv.Atoi(digits)
+		if err != nil {
+			continue
+		}
+
+		seen[index] = struct{}{}
+	}
+
+	return slices.Sorted(maps.Keys(seen))
+}
+
+func isCanonicalIndex(digits string) bool {
+	if digits == "" || len(digits) > 1 && digits[0] == '0' {
+		return false
+	}
+
+	return strings.Trim(digits, "0123456789") == ""
+}
@@ -0,0 +1,181 @@
+package gonfiguration_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/psyb0t/gonfiguration"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseIndexedStructSlice(t *testing.T) {
+	type Upstream struct {
+		Host    string        `env:"HOST,required"`
+		Port    int           `env:"PORT" default:"80"`
+		Timeout time.Duration `env:"TIMEOUT" default:"5s"`
+		Tags    []string      `env:"TAGS"`
+	}
+
+	type Config struct {
+		Name      string     `env:"NAME"`
+		Upstreams []Upstream `env:"UPSTREAM"`
+	}
+
+	t.Run("contiguous indices", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		t.Setenv("UPSTREAM_0_HOST", "a.internal")
+		t.Setenv("UPSTREAM_0_PORT", "8080")
+		t.Setenv("UPSTREAM_1_HOST", "b.internal")
+		t.Setenv("UPSTREAM_1_TAGS", "eu,backup")
+		t.Setenv("UPSTREAM_2_HOST", "c.internal")
+		t.Setenv("UPSTREAM_2_TIMEOUT", "1m")
+
+		cfg := Config{}
+		require.NoError(t, gonfiguration.Parse(&cfg))
+		require.Equal(t, []Upstream{
+			{Host: "a.internal", Port: 8080, Timeout: 5 * time.Second},
+			{Host: "b.internal", Port: 80, Timeout: 5 * time.Second, Tags: []string{"eu", "backup"}},
+			{Host: "c.internal", Port: 80, Timeout: time.Minute},
+		}, cfg.Upstreams)
+	})
+
+	t.Run("ten or more elements sort numerically", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		for _, index := range []string{"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "10"} {
+			t.Setenv("UPSTREAM_"+index+"_HOST", "host-"+index)
+		}
+
+		cfg := Config{}
+		require.NoError(t, gonfiguration.Parse(&cfg))
+		require.Len(t, cfg.Upstreams, 11)
+		require.Equal(t, "host-10", cfg.Upstreams[10].Host)
+	})
+
+	t.Run("element required field", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		t.Setenv("UPSTREAM_0_HOST", "a.internal")
+		t.Setenv("UPSTREAM_1_PORT", "81")
+
+		cfg := Config{}
+		err := gonfiguration.Parse(&cfg)
+		require.ErrorIs(t, err, gonfiguration.ErrRequiredFieldNotSet)
+		require.Contains(t, err.Error(), "field UPSTREAM_1_HOST")
+	})
+
+	t.Run("element invalid value", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		t.Setenv("UPSTREAM_0_HOST", "a.internal")
+		t.Setenv("UPSTREAM_0_PORT", "http")
+
+		cfg := Config{}
+		err := gonfiguration.Parse(&cfg)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "field UPSTREAM_0_PORT")
+	})
+
+	t.Run("gap rejected", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		t.Setenv("UPSTREAM_0_HOST", "a.internal")
+		t.Setenv("UPSTREAM_2_HOST", "c.internal")
+
+		cfg := Config{}
+		err := gonfiguration.Parse(&cfg)
+		require.ErrorIs(t, err, gonfiguration.ErrIndexGap)
+		require.Contains(t, err.Error(), "missing index 1")
+	})
+
+	t.Run("sparse option packs elements", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		type SparseConfig struct {
+			Upstreams []Upstream `env:"UPSTREAM,sparse"`
+		}
+
+		t.Setenv("UPSTREAM_3_HOST", "d.internal")
+		t.Setenv("UPSTREAM_1_HOST", "b.internal")
+
+		cfg := SparseConfig{}
+		require.NoError(t, gonfiguration.Parse(&cfg))
+		require.Len(t, cfg.Upstreams, 2)
+		require.Equal(t, "b.internal", cfg.Upstreams[0].Host)
+		require.Equal(t, "d.internal", cfg.Upstreams[1].Host)
+	})
+
+	t.Run("non-canonical indices ignored", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		t.Setenv("UPSTREAM_0_HOST", "a.internal")
+		t.Setenv("UPSTREAM_01_HOST", "ignored")
+		t.Setenv("UPSTREAM_X_HOST", "ignored")
+		t.Setenv("UPSTREAM_+1_HOST", "ignored")
+		t.Setenv("UPSTREAMS_1_HOST", "ignored")
+
+		cfg := Config{}
+		require.NoError(t, gonfiguration.Parse(&cfg))
+		require.Len(t, cfg.Upstreams, 1)
+	})
+
+	t.Run("none present", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		cfg := Config{}
+		require.NoError(t, gonfiguration.Parse(&cfg))
+		require.Nil(t, cfg.Upstreams)
+	})
+
+	t.Run("none present falls back to programmatic default", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		gonfiguration.SetDefault("UPSTREAM", []Upstream{{Host: "fallback"}})
+
+		cfg := Config{}
+		require.NoError(t, gonfiguration.Parse(&cfg))
+		require.Equal(t, []Upstream{{Host: "fallback"}}, cfg.Upstreams)
+	})
+
+	t.Run("none present but required", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		type RequiredConfig struct {
+			Upstreams []Upstream `env:"UPSTREAM,required"`
+		}
+
+		cfg := RequiredConfig{}
+		err := gonfiguration.Parse(&cfg)
+		require.ErrorIs(t, err, gonfiguration.ErrRequiredFieldNotSet)
+		require.Contains(t, err.Error(), "field UPSTREAM")
+	})
+
+	t.Run("nested indexed slices", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		type Backend struct {
+			Addr string `env:"ADDR"`
+		}
+
+		type Pool struct {
+			Name     string    `env:"NAME"`
+			Backends []Backend `env:"BACKEND"`
+		}
+
+		type NestedConfig struct {
+			Pools []Pool `env:"POOL"`
+		}
+
+		t.Setenv("POOL_0_NAME", "web")
+		t.Setenv("POOL_0_BACKEND_0_ADDR", "10.0.0.1")
+		t.Setenv("POOL_0_BACKEND_1_ADDR", "10.0.0.2")
+
+		cfg := NestedConfig{}
+		require.NoError(t, gonfiguration.Parse(&cfg))
+		require.Equal(t, []Pool{{
+			Name:     "web",
+			Backends: []Backend{{Addr: "10.0.0.1"}, {Addr: "10.0.0.2"}},
+		}}, cfg.Pools)
+	})
+}
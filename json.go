@@ -0,0 +1,92 @@
+package gonfiguration
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+
+	"github.com/psyb0t/ctxerrors"
+)
+
+// jsonErrorContext is how many bytes around a JSON error offset are
+// quoted in the error message.
+const jsonErrorContext = 16
+
+// setJSON decodes envVal into a fresh value of the field's type, so a
+// JSON value replaces a default instead of being merged into it.
+func setJSON(
+	fieldValue reflect.Value,
+	envVal string,
+) error {
+	decoded := reflect.New(fieldValue.Type())
+
+	if err := json.Unmarshal([]byte(envVal), decoded.Interface()); err != nil {
+		return describeJSONError(envVal, err)
+	}
+
+	fieldValue.Set(decoded.Elem())
+
+	return nil
+}
+
+// jsonError is ErrInvalidJSON carrying the decoder's error, so both
+// errors.Is(err, ErrInvalidJSON) and errors.As into *json.SyntaxError or
+// *json.UnmarshalTypeError work on what Parse returns.
+type jsonError struct {
+	err error
+}
+
+func (e jsonError) Error() string {
+	return ErrInvalidJSON.Error() + ": " + e.err.Error()
+}
+
+func (e jsonError) Unwrap() []error {
+	return []error{ErrInvalidJSON, e.err}
+}
+
+// describeJSONError turns the byte offset carried by encoding/json errors
+// into a line, column and snippet of the offending input.
+func describeJSONError(data string, err error) error {
+	var (
+		syntaxErr *json.SyntaxError
+		typeErr   *json.UnmarshalTypeError
+		offset    int64
+	)
+
+	switch {
+	case errors.As(err, &syntaxErr):
+		offset = syntaxErr.Offset
+	case errors.As(err, &typeErr):
+		offset = typeErr.Offset
+	default:
+		return ctxerrors.Wrap(jsonError{err: err}, "failed to decode JSON")
+	}
+
+	// The offset counts the bytes read when decoding failed, so the
+	// offending byte is the last one of those
+	pos := min(max(int(offset)-1, 0), len(data))
+	line, column := bytePosition(data, pos)
+
+	return ctxerrors.Wrapf(
+		jsonError{err: err},
+		"line %d, column %d near %q",
+		line, column, snippetAround(data, pos),
+	)
+}
+
+// bytePosition converts a byte index into a 1-based line and column.
+func bytePosition(data string, pos int) (int, int) {
+	before := data[:pos]
+	line := strings.Count(before, "\n") + 1
+	column := pos - strings.LastIndex(before, "\n")
+
+	return line, column
+}
+
+func snippetAround(data string, pos int) string {
+	start := max(pos-jsonErrorContext, 0)
+	end := min(pos+jsonErrorContext, len(data))
+
+	return data[start:end]
+}
@@ -0,0 +1,113 @@
+package gonfiguration_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/psyb0t/gonfiguration"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseJSON(t *testing.T) {
+	type Upstream struct {
+		Host    string   `json:"host"`
+		Port    int      `json:"port"`
+		Weights []int    `json:"weights"`
+		Tags    []string `json:"tags"`
+	}
+
+	type Config struct {
+		Upstreams []Upstream          `env:"UPSTREAMS,json"`
+		Routes    map[string]Upstream `env:"ROUTES,json,required"`
+		Limits    map[string]int      `env:"LIMITS,json" default:"{\"default\": 10}"`
+	}
+
+	t.Run("slices of structs and maps", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		t.Setenv("UPSTREAMS", `[{"host":"a","port":80,"tags":["x,y"]},{"host":"b","port":81}]`)
+		t.Setenv("ROUTES", `{"/api": {"host": "api", "port": 8080}}`)
+
+		cfg := Config{}
+		require.NoError(t, gonfiguration.Parse(&cfg))
+		require.Equal(t, []Upstream{
+			{Host: "a", Port: 80, Tags: []string{"x,y"}},
+			{Host: "b", Port: 81},
+		}, cfg.Upstreams)
+		require.Equal(t, map[string]Upstream{"/api": {Host: "api", Port: 8080}}, cfg.Routes)
+		require.Equal(t, map[string]int{"default": 10}, cfg.Limits)
+	})
+
+	t.Run("env value replaces default instead of merging", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		t.Setenv("ROUTES", `{}`)
+		t.Setenv("LIMITS", `{"burst": 5}`)
+
+		cfg := Config{}
+		require.NoError(t, gonfiguration.Parse(&cfg))
+		require.Equal(t, map[string]int{"burst": 5}, cfg.Limits)
+	})
+
+	t.Run("required still enforced", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		cfg := Config{}
+		require.ErrorIs(t, gonfiguration.Parse(&cfg), gonfiguration.ErrRequiredFieldNotSet)
+	})
+
+	t.Run("syntax error names key and position", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		t.Setenv("ROUTES", "{}")
+		t.Setenv("UPSTREAMS", "[\n  {\"host\": \"a\",}\n]")
+
+		cfg := Config{}
+		err := gonfiguration.Parse(&cfg)
+		require.ErrorIs(t, err, gonfiguration.ErrInvalidJSON)
+		require.Contains(t, err.Error(), "field UPSTREAMS")
+		require.Contains(t, err.Error(), "line 2, column 16")
+
+		var syntaxErr *json.SyntaxError
+		require.ErrorAs(t, err, &syntaxErr)
+	})
+
+	t.Run("type error names key and position", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		t.Setenv("ROUTES", `{"/": {"host": "a", "port": "80"}}`)
+
+		cfg := Config{}
+		err := gonfiguration.Parse(&cfg)
+		require.ErrorIs(t, err, gonfiguration.ErrInvalidJSON)
+		require.Contains(t, err.Error(), "field ROUTES")
+		require.Contains(t, err.Error(), "line 1, column 32")
+
+		var typeErr *json.UnmarshalTypeError
+		require.ErrorAs(t, err, &typeErr)
+		require.Equal(t, "string", typeErr.Value)
+	})
+
+	t.Run("empty value", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		t.Setenv("ROUTES", "")
+
+		cfg := Config{}
+		require.ErrorIs(t, gonfiguration.Parse(&cfg), gonfiguration.ErrInvalidJSON)
+	})
+
+	t.Run("scalar field", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		type ScalarConfig struct {
+			Name string `env:"NAME,json"`
+		}
+
+		t.Setenv("NAME", `"quoted \"name\""`)
+
+		cfg := ScalarConfig{}
+		require.NoError(t, gonfiguration.Parse(&cfg))
+		require.Equal(t, `quoted "name"`, cfg.Name)
+	})
+}
@@ -0,0 +1,79 @@
+package gonfiguration
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/psyb0t/ctxerrors"
+)
+
+func newParser(envVars map[string]string, opts []Option) *parser {
+	p := &parser{
+		envVars: envVars,
+		options: newOptions(opts),
+		known:   map[string]struct{}{},
+	}
+
+	if p.options.caseInsensitive {
+		p.envVars, p.foldConflicts = foldEnvVars(envVars)
+	}
+
+	return p
+}
+
+// foldEnvVars upper-cases the keys of envVars. Keys that fold to the same
+// name with the same value merge; the ones with different values are
+// returned as conflicts, keyed by the folded name, and only fail a parse
+// when a field actually reads them.
+func foldEnvVars(envVars map[string]string) (map[string]string, map[string][]string) {
+	folded := make(map[string]string, len(envVars))
+	variants := map[string][]string{}
+
+	for key, val := range envVars {
+		foldedKey := strings.ToUpper(key)
+		variants[foldedKey] = append(variants[foldedKey], key)
+		folded[foldedKey] = val
+	}
+
+	conflicts := map[string][]string{}
+
+	for foldedKey, keys := range variants {
+		for _, key := range keys[1:] {
+			if envVars[key] != envVars[keys[0]] {
+				slices.Sort(keys)
+				conflicts[foldedKey] = keys
+
+				break
+			}
+		}
+	}
+
+	return folded, conflicts
+}
+
+// envKey returns the key under which key is stored in the env snapshot.
+func (p *parser) envKey(key string) string {
+	if p.options.caseInsensitive {
+		return strings.ToUpper(key)
+	}
+
+	return key
+}
+
+// get returns the env value of key, matching case-insensitively if the
+// parse was asked to, and records key as known to the destination.
+func (p *parser) get(key string) (string, bool, error) {
+	envKey := p.envKey(key)
+	p.known[envKey] = struct{}{}
+
+	if keys, ok := p.foldConflicts[envKey]; ok {
+		return "", false, ctxerrors.Wrapf(
+			ErrAmbiguousKey,
+			"%s is set as %s with different values", key, strings.Join(keys, ", "),
+		)
+	}
+
+	val, ok := p.envVars[envKey]
+
+	return val, ok, nil
+}
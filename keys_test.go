@@ -0,0 +1,92 @@
+package gonfiguration_test
+
+import (
+	"testing"
+
+	"github.com/psyb0t/gonfiguration"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCaseInsensitiveKeys(t *testing.T) {
+	type Upstream struct {
+		Host string `env:"HOST"`
+	}
+
+	type Config struct {
+		DatabaseURL string     `env:"DATABASE_URL,alias=DB_URL"`
+		Port        int        `env:"PORT"`
+		Upstreams   []Upstream `env:"UPSTREAM"`
+	}
+
+	parse := func(cfg *Config) error {
+		return gonfiguration.Parse(cfg, gonfiguration.WithCaseInsensitiveKeys())
+	}
+
+	t.Run("any case matches", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		t.Setenv("database_url", "postgres://lower")
+		t.Setenv("Port", "8080")
+		t.Setenv("upstream_0_host", "a.internal")
+		t.Setenv("Upstream_1_Host", "b.internal")
+
+		cfg := Config{}
+		require.NoError(t, parse(&cfg))
+		require.Equal(t, "postgres://lower", cfg.DatabaseURL)
+		require.Equal(t, 8080, cfg.Port)
+		require.Equal(t, []Upstream{{Host: "a.internal"}, {Host: "b.internal"}}, cfg.Upstreams)
+	})
+
+	t.Run("aliases match in any case", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		t.Setenv("db_url", "postgres://old")
+
+		cfg := Config{}
+		require.NoError(t, parse(&cfg))
+		require.Equal(t, "postgres://old", cfg.DatabaseURL)
+	})
+
+	t.Run("variants with the same value", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		t.Setenv("PORT", "8080")
+		t.Setenv("port", "8080")
+
+		cfg := Config{}
+		require.NoError(t, parse(&cfg))
+		require.Equal(t, 8080, cfg.Port)
+	})
+
+	t.Run("variants with different values", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		t.Setenv("PORT", "8080")
+		t.Setenv("port", "9090")
+
+		cfg := Config{}
+		err := parse(&cfg)
+		require.ErrorIs(t, err, gonfiguration.ErrAmbiguousKey)
+		require.Contains(t, err.Error(), "PORT, port")
+	})
+
+	t.Run("unread conflicting variants are ignored", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		t.Setenv("UNRELATED", "a")
+		t.Setenv("unrelated", "b")
+
+		cfg := Config{}
+		require.NoError(t, parse(&cfg))
+	})
+
+	t.Run("case sensitive by default", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		t.Setenv("port", "8080")
+
+		cfg := Config{}
+		require.NoError(t, gonfiguration.Parse(&cfg))
+		require.Zero(t, cfg.Port)
+	})
+}
@@ -0,0 +1,243 @@
+package gonfiguration
+
+import (
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/psyb0t/ctxerrors"
+)
+
+const (
+	defaultListSeparator = ","
+	listQuote            = `"`
+
+	// Values for the `list` tag.
+	listOptionDropEmpty = "dropempty"
+	listOptionDedupe    = "dedupe"
+)
+
+func setSlice(
+	fieldValue reflect.Value,
+	envVal string,
+	format valueFormat,
+) error {
+	items, err := splitList(envVal, format)
+	if err != nil {
+		return err
+	}
+
+	slice := reflect.MakeSlice(fieldValue.Type(), len(items), len(items))
+
+	if err := setListItems(slice, items, format); err != nil {
+		return err
+	}
+
+	fieldValue.Set(slice)
+
+	return nil
+}
+
+func setArray(
+	fieldValue reflect.Value,
+	envVal string,
+	format valueFormat,
+) error {
+	items, err := splitList(envVal, format)
+	if err != nil {
+		return err
+	}
+
+	if len(items) != fieldValue.Len() {
+		return ctxerrors.Wrapf(
+			ErrLengthMismatch,
+			"expected %d items, got %d", fieldValue.Len(), len(items),
+		)
+	}
+
+	// Parse into a copy so a bad item doesn't leave the field half-set
+	array := reflect.New(fieldValue.Type()).Elem()
+
+	if err := setListItems(array, items, format); err != nil {
+		return err
+	}
+
+	fieldValue.Set(array)
+
+	return nil
+}
+
+func setListItems(
+	list reflect.Value,
+	items []string,
+	format valueFormat,
+) error {
+	for i, item := range items {
+		if err := setEnvVarValue(list.Index(i), item, format); err != nil {
+			return ctxerrors.Wrapf(err, "item %d", i)
+		}
+	}
+
+	return nil
+}
+
+// splitList splits a delimited value into items. Items are trimmed and
+// may be double-quoted RFC 4180 style, so `"a,b",c` is two items and ""
+// inside quotes is a literal quote. Empty items are kept unless the field
+// asks to drop them, quoted ones always survive. An empty value is an
+// empty list rather than a list with one empty item.
+func splitList(envVal string, format valueFormat) ([]string, error) {
+	items := []string{}
+	if envVal == "" {
+		return items, nil
+	}
+
+	sep := format.sep
+	if sep == "" {
+		sep = defaultListSeparator
+	}
+
+	scanner := &listScanner{rest: envVal, sep: sep}
+	seen := map[string]struct{}{}
+
+	for !scanner.done {
+		item, quoted, err := scanner.next()
+		if err != nil {
+			return nil, err
+		}
+
+		if item == "" && !quoted && format.dropEmpty {
+			continue
+		}
+
+		if format.dedupe {
+			if _, ok := seen[item]; ok {
+				continue
+			}
+
+			seen[item] = struct{}{}
+		}
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// listScanner walks a delimited value one item at a time.
+type listScanner struct {
+	rest string
+	sep  string
+	done bool
+}
+
+// next consumes one item and reports whether it was quoted. done is set
+// once the last item has been consumed.
+func (s *listScanner) next() (string, bool, error) {
+	trimmed := strings.TrimLeftFunc(s.rest, unicode.IsSpace)
+	if !strings.HasPrefix(trimmed, listQuote) {
+		item, after, found := strings.Cut(s.rest, s.sep)
+		s.rest, s.done = after, !found
+
+		return strings.TrimSpace(item), false, nil
+	}
+
+	item, after, err := unquoteListItem(trimmed[len(listQuote):])
+	if err != nil {
+		return "", false, err
+	}
+
+	// Whitespace between the closing quote and the separator is padding,
+	// unless the separator is whitespace itself
+	if strings.TrimSpace(s.sep) != "" {
+		after = strings.TrimLeftFunc(after, unicode.IsSpace)
+	}
+
+	switch {
+	case after == "":
+		s.rest, s.done = "", true
+	case strings.HasPrefix(after, s.sep):
+		s.rest = after[len(s.sep):]
+	default:
+		return "", false, ctxerrors.Wrapf(
+			ErrInvalidList,
+			"unexpected %q after quoted item", after,
+		)
+	}
+
+	return item, true, nil
+}
+
+// unquoteListItem reads a quoted item up to its closing quote, with the
+// opening quote already consumed, and returns it along with the rest.
+func unquoteListItem(val string) (string, string, error) {
+	var item strings.Builder
+
+	for rest := val; ; {
+		idx := strings.Index(rest, listQuote)
+		if idx < 0 {
+			return "", "", ctxerrors.Wrapf(ErrInvalidList, "unterminated quote in %q", val)
+		}
+
+		item.WriteString(rest[:idx])
+		rest = rest[idx+len(listQuote):]
+
+		if !strings.HasPrefix(rest, listQuote) {
+			return item.String(), rest, nil
+		}
+
+		item.WriteString(listQuote)
+		rest = rest[len(listQuote):]
+	}
+}
+
+// joinList is the inverse of splitList: items are joined with the
+// field's separator and quoted when they'd otherwise be split, trimmed
+// or dropped.
+func joinList(items []string, format valueFormat) string {
+	sep := format.sep
+	if sep == "" {
+		sep = defaultListSeparator
+	}
+
+	quoted := make([]string, len(items))
+
+	for i, item := range items {
+		if item == "" || item != strings.TrimSpace(item) ||
+			strings.Contains(item, sep) || strings.Contains(item, listQuote) {
+			item = listQuote + strings.ReplaceAll(item, listQuote, listQuote+listQuote) + listQuote
+		}
+
+		quoted[i] = item
+	}
+
+	return strings.Join(quoted, sep)
+}
+
+func isListKind(kind reflect.Kind) bool {
+	return kind == reflect.Slice || kind == reflect.Array
+}
+
+// isDelimitedList reports whether a value of typ is parsed as a
+// delimited list of items rather than as one value.
+func isDelimitedList(typ reflect.Type) bool {
+	_, hasSetter := typeSetters[typ]
+
+	return isListKind(typ.Kind()) && !hasSetter && !isByteSequence(typ)
+}
+
+// isSupportedListElem reports whether typ can be an item of a slice or
+// array field. Lists of lists would need a second delimiter, so items
+// can't be lists themselves unless they are binary or have a setter of
+// their own, like net.IP.
+func isSupportedListElem(typ reflect.Type) bool {
+	if _, ok := typeSetters[typ]; ok {
+		return true
+	}
+
+	if isByteSequence(typ) {
+		return true
+	}
+
+	return !isListKind(typ.Kind()) && isSupportedValueType(typ)
+}
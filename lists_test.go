@@ -0,0 +1,277 @@
+package gonfiguration_test
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/psyb0t/gonfiguration"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseArray(t *testing.T) {
+	type Config struct {
+		ReplicaSet [3]string         `env:"REPLICA_SET"`
+		LatLon     [2]float64        `env:"LAT_LON" default:"44.43, 26.10"`
+		Resolvers  [2]netip.AddrPort `env:"RESOLVERS"`
+	}
+
+	t.Run("exact item count", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		t.Setenv("REPLICA_SET", "db-0, db-1, db-2")
+		t.Setenv("RESOLVERS", "1.1.1.1:53,8.8.8.8:53")
+
+		cfg := Config{}
+		require.NoError(t, gonfiguration.Parse(&cfg))
+		require.Equal(t, [3]string{"db-0", "db-1", "db-2"}, cfg.ReplicaSet)
+		require.Equal(t, [2]float64{44.43, 26.10}, cfg.LatLon)
+		require.Equal(t, netip.MustParseAddrPort("8.8.8.8:53"), cfg.Resolvers[1])
+	})
+
+	t.Run("programmatic default", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		gonfiguration.SetDefault("REPLICA_SET", [3]string{"a", "b", "c"})
+
+		cfg := Config{}
+		require.NoError(t, gonfiguration.Parse(&cfg))
+		require.Equal(t, [3]string{"a", "b", "c"}, cfg.ReplicaSet)
+	})
+
+	t.Run("too few items", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		t.Setenv("REPLICA_SET", "db-0,db-1")
+
+		cfg := Config{}
+		err := gonfiguration.Parse(&cfg)
+		require.ErrorIs(t, err, gonfiguration.ErrLengthMismatch)
+		require.Contains(t, err.Error(), "field REPLICA_SET")
+		require.Contains(t, err.Error(), "expected 3 items, got 2")
+	})
+
+	t.Run("too many items", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		t.Setenv("LAT_LON", "1,2,3")
+
+		cfg := Config{}
+		require.ErrorIs(t, gonfiguration.Parse(&cfg), gonfiguration.ErrLengthMismatch)
+	})
+
+	t.Run("empty value", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		t.Setenv("LAT_LON", "")
+
+		cfg := Config{}
+		require.ErrorIs(t, gonfiguration.Parse(&cfg), gonfiguration.ErrLengthMismatch)
+	})
+
+	t.Run("invalid item", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		t.Setenv("LAT_LON", "1.5,north")
+
+		cfg := Config{}
+		err := gonfiguration.Parse(&cfg)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "item 1")
+	})
+}
+
+func TestParseListFormat(t *testing.T) {
+	testCases := []struct {
+		name     string
+		dst      func() any
+		value    string
+		expected any
+		errorIs  error
+	}{
+		{
+			name: "default separator trims and keeps empty items",
+			dst: func() any {
+				return &struct {
+					V []string `env:"LIST"`
+				}{}
+			},
+			value: " a, ,b,, c ,",
+			expected: &struct {
+				V []string `env:"LIST"`
+			}{V: []string{"a", "", "b", "", "c", ""}},
+		},
+		{
+			name: "custom separator",
+			dst: func() any {
+				return &struct {
+					V []string `env:"LIST" sep:";"`
+				}{}
+			},
+			value: "host=db user=a,b; host=replica",
+			expected: &struct {
+				V []string `env:"LIST" sep:";"`
+			}{V: []string{"host=db user=a,b", "host=replica"}},
+		},
+		{
+			name: "multi-character separator",
+			dst: func() any {
+				return &struct {
+					V []int `env:"LIST" sep:"::"`
+				}{}
+			},
+			value: "1::2:: 3",
+			expected: &struct {
+				V []int `env:"LIST" sep:"::"`
+			}{V: []int{1, 2, 3}},
+		},
+		{
+			name: "whitespace separator",
+			dst: func() any {
+				return &struct {
+					V []string `env:"LIST" sep:" "`
+				}{}
+			},
+			value: `a "b c" d`,
+			expected: &struct {
+				V []string `env:"LIST" sep:" "`
+			}{V: []string{"a", "b c", "d"}},
+		},
+		{
+			name: "quoted items keep separators and spaces",
+			dst: func() any {
+				return &struct {
+					V []string `env:"LIST"`
+				}{}
+			},
+			value: `"a,b", c, " padded "`,
+			expected: &struct {
+				V []string `env:"LIST"`
+			}{V: []string{"a,b", "c", " padded "}},
+		},
+		{
+			name: "escaped quotes",
+			dst: func() any {
+				return &struct {
+					V []string `env:"LIST"`
+				}{}
+			},
+			value: `"say ""hi""",x`,
+			expected: &struct {
+				V []string `env:"LIST"`
+			}{V: []string{`say "hi"`, "x"}},
+		},
+		{
+			name: "quoted empty item survives",
+			dst: func() any {
+				return &struct {
+					V []string `env:"LIST"`
+				}{}
+			},
+			value: `a,"",b`,
+			expected: &struct {
+				V []string `env:"LIST"`
+			}{V: []string{"a", "", "b"}},
+		},
+		{
+			name: "drop empty items",
+			dst: func() any {
+				return &struct {
+					V []string `env:"LIST" list:"dropempty"`
+				}{}
+			},
+			value: `a,,b,,"",`,
+			expected: &struct {
+				V []string `env:"LIST" list:"dropempty"`
+			}{V: []string{"a", "b", ""}},
+		},
+		{
+			name: "dedupe",
+			dst: func() any {
+				return &struct {
+					V []string `env:"LIST" list:"dedupe"`
+				}{}
+			},
+			value: "b,a,b,c,a",
+			expected: &struct {
+				V []string `env:"LIST" list:"dedupe"`
+			}{V: []string{"b", "a", "c"}},
+		},
+		{
+			name: "dedupe before array length check",
+			dst: func() any {
+				return &struct {
+					V [2]string `env:"LIST" list:"dedupe"`
+				}{}
+			},
+			value: "a,a,b",
+			expected: &struct {
+				V [2]string `env:"LIST" list:"dedupe"`
+			}{V: [2]string{"a", "b"}},
+		},
+		{
+			name: "regexp with comma quantifier",
+			dst: func() any {
+				return &struct {
+					V []string `env:"LIST"`
+				}{}
+			},
+			value: `"^a{1,3}$",^b$`,
+			expected: &struct {
+				V []string `env:"LIST"`
+			}{V: []string{"^a{1,3}$", "^b$"}},
+		},
+		{
+			name: "unterminated quote",
+			dst: func() any {
+				return &struct {
+					V []string `env:"LIST"`
+				}{}
+			},
+			value:   `"a,b`,
+			errorIs: gonfiguration.ErrInvalidList,
+		},
+		{
+			name: "garbage after quoted item",
+			dst: func() any {
+				return &struct {
+					V []string `env:"LIST"`
+				}{}
+			},
+			value:   `"a"b,c`,
+			errorIs: gonfiguration.ErrInvalidList,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			defer gonfiguration.Reset()
+
+			t.Setenv("LIST", tc.value)
+
+			dst := tc.dst()
+			err := gonfiguration.Parse(dst)
+
+			if tc.errorIs != nil {
+				require.ErrorIs(t, err, tc.errorIs)
+				require.Contains(t, err.Error(), "field LIST")
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, dst)
+		})
+	}
+
+	t.Run("default tag follows the same rules", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		type Config struct {
+			Crons []string `env:"CRONS" sep:";" list:"dropempty" default:"0 * * * *; \"*/5 * * * *\";"`
+		}
+
+		cfg := Config{}
+		require.NoError(t, gonfiguration.Parse(&cfg))
+		require.Equal(t, []string{"0 * * * *", "*/5 * * * *"}, cfg.Crons)
+	})
+}
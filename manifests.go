@@ -0,0 +1,100 @@
+package gonfiguration
+
+import (
+	"fmt"
+	"strings"
+)
+
+// KubernetesEnv renders the docs as the env list of a Kubernetes
+// container spec. Secret variables are secretKeyRefs into the Secret
+// named secretName, and so are a certificate's private key vars. The
+// rest are configMapKeyRefs into configMapName or, when it's empty,
+// literal defaults. Variables with no default are left out of the literal
+// list, since an empty value isn't the same as unset and would get past
+// the required check; required ones get a comment in their place. Refs to
+// optional variables are marked optional. Every key is also the key in the
+// Secret or ConfigMap.
+func (d Docs) KubernetesEnv(secretName, configMapName string) string {
+	var b strings.Builder
+
+	b.WriteString("env:\n")
+
+	for _, doc := range d {
+		ref := ""
+
+		switch {
+		case doc.Secret:
+			ref = "secretKeyRef"
+		case configMapName != "":
+			ref = "configMapKeyRef"
+		case !doc.HasDefault && doc.Required:
+			doc.writeManifestComment(&b)
+			fmt.Fprintf(&b, "  # %s is required and has no default: add it before deploying\n", doc.exampleKey())
+
+			continue
+		case !doc.HasDefault:
+			continue
+		}
+
+		doc.writeManifestComment(&b)
+
+		key := doc.exampleKey()
+		fmt.Fprintf(&b, "  - name: %s\n", yamlKey(key))
+
+		if ref == "" {
+			fmt.Fprintf(&b, "    value: %s\n", yamlString(doc.Default))
+
+			continue
+		}
+
+		name := configMapName
+		if doc.Secret {
+			name = secretName
+		}
+
+		fmt.Fprintf(&b, "    valueFrom:\n      %s:\n", ref)
+		fmt.Fprintf(&b, "        name: %s\n        key: %s\n", yamlString(name), yamlString(key))
+
+		if !doc.Required {
+			b.WriteString("        optional: true\n")
+		}
+	}
+
+	return b.String()
+}
+
+// ComposeEnvironment renders the docs as a docker-compose environment
+// block that takes each value from the shell or .env file compose runs
+// with: required variables fail the interpolation when unset, defaults
+// become ${KEY:-default}, and secrets and optional variables with no
+// default are passed through only when set, so no secret default ends up
+// in the compose file.
+func (d Docs) ComposeEnvironment() string {
+	var b strings.Builder
+
+	b.WriteString("environment:\n")
+
+	for _, doc := range d {
+		doc.writeManifestComment(&b)
+
+		key := doc.exampleKey()
+
+		switch {
+		case doc.Required && !doc.HasDefault:
+			fmt.Fprintf(&b, "  %s: %s\n", yamlKey(key), yamlString("${"+key+":?"+key+" is required}"))
+		case doc.Secret || !doc.HasDefault:
+			fmt.Fprintf(&b, "  %s:\n", yamlKey(key))
+		default:
+			value := strings.ReplaceAll(doc.Default, "$", "$$")
+			fmt.Fprintf(&b, "  %s: %s\n", yamlKey(key), yamlString("${"+key+":-"+value+"}"))
+		}
+	}
+
+	return b.String()
+}
+
+func (d VarDoc) writeManifestComment(b *strings.Builder) {
+	if d.Description != "" {
+		fmt.Fprintf(b, "  # %s\n", d.Description)
+	}
+}
@@ -0,0 +1,156 @@
+package gonfiguration_test
+
+import (
+	"crypto/tls"
+	"testing"
+
+	"github.com/psyb0t/gonfiguration"
+	"github.com/stretchr/testify/require"
+)
+
+type ManifestConfig struct {
+	Port     int                          `env:"PORT" default:"8080" desc:"Port to listen on."`
+	DBURL    string                       `env:"DATABASE_URL,required"`
+	Token    gonfiguration.Secret[string] `env:"TOKEN,required"`
+	APIKey   string                       `env:"API_KEY,secret" default:"dev-key"`
+	Greeting string                       `env:"GREETING" default:"hi $USER"`
+	Region   string                       `env:"REGION"`
+	Cert     tls.Certificate              `env:"TLS_CERT,required" tlskey:"TLS_KEY"`
+	Bundle   tls.Certificate              `env:"TLS_BUNDLE"`
+}
+
+func TestDocsKubernetesEnv(t *testing.T) {
+	defer gonfiguration.Reset()
+
+	docs, err := gonfiguration.Describe(ManifestConfig{})
+	require.NoError(t, err)
+
+	t.Run("literal values", func(t *testing.T) {
+		require.Equal(t, `env:
+  # Port to listen on.
+  - name: PORT
+    value: "8080"
+  # DATABASE_URL is required and has no default: add it before deploying
+  - name: TOKEN
+    valueFrom:
+      secretKeyRef:
+        name: "api-secrets"
+        key: "TOKEN"
+  - name: API_KEY
+    valueFrom:
+      secretKeyRef:
+        name: "api-secrets"
+        key: "API_KEY"
+        optional: true
+  - name: GREETING
+    value: "hi $USER"
+  # TLS_CERT is required and has no default: add it before deploying
+  # Private key of TLS_CERT.
+  - name: TLS_KEY
+    valueFrom:
+      secretKeyRef:
+        name: "api-secrets"
+        key: "TLS_KEY"
+  - name: TLS_BUNDLE
+    valueFrom:
+      secretKeyRef:
+        name: "api-secrets"
+        key: "TLS_BUNDLE"
+        optional: true
+`, docs.KubernetesEnv("api-secrets", ""))
+	})
+
+	t.Run("config map refs", func(t *testing.T) {
+		require.Equal(t, `env:
+  # Port to listen on.
+  - name: PORT
+    valueFrom:
+      configMapKeyRef:
+        name: "api-config"
+        key: "PORT"
+        optional: true
+  - name: DATABASE_URL
+    valueFrom:
+      configMapKeyRef:
+        name: "api-config"
+        key: "DATABASE_URL"
+  - name: TOKEN
+    valueFrom:
+      secretKeyRef:
+        name: "api-secrets"
+        key: "TOKEN"
+  - name: API_KEY
+    valueFrom:
+      secretKeyRef:
+        name: "api-secrets"
+        key: "API_KEY"
+        optional: true
+  - name: GREETING
+    valueFrom:
+      configMapKeyRef:
+        name: "api-config"
+        key: "GREETING"
+        optional: true
+  - name: REGION
+    valueFrom:
+      configMapKeyRef:
+        name: "api-config"
+        key: "REGION"
+        optional: true
+  - name: TLS_CERT
+    valueFrom:
+      configMapKeyRef:
+        name: "api-config"
+        key: "TLS_CERT"
+  # Private key of TLS_CERT.
+  - name: TLS_KEY
+    valueFrom:
+      secretKeyRef:
+        name: "api-secrets"
+        key: "TLS_KEY"
+  - name: TLS_BUNDLE
+    valueFrom:
+      secretKeyRef:
+        name: "api-secrets"
+        key: "TLS_BUNDLE"
+        optional: true
+`, docs.KubernetesEnv("api-secrets", "api-config"))
+	})
+}
+
+func TestDocsComposeEnvironment(t *testing.T) {
+	defer gonfiguration.Reset()
+
+	docs, err := gonfiguration.Describe(ManifestConfig{})
+	require.NoError(t, err)
+	require.Equal(t, `environment:
+  # Port to listen on.
+  PORT: "${PORT:-8080}"
+  DATABASE_URL: "${DATABASE_URL:?DATABASE_URL is required}"
+  TOKEN: "${TOKEN:?TOKEN is required}"
+  API_KEY:
+  GREETING: "${GREETING:-hi $$USER}"
+  REGION:
+  TLS_CERT: "${TLS_CERT:?TLS_CERT is required}"
+  # Private key of TLS_CERT.
+  TLS_KEY: "${TLS_KEY:?TLS_KEY is required}"
+  TLS_BUNDLE:
+`, docs.ComposeEnvironment())
+}
+
+func TestDocsManifestsPatternKeys(t *testing.T) {
+	defer gonfiguration.Reset()
+
+	type Upstream struct {
+		Host string `env:"HOST" default:"localhost"`
+	}
+
+	type Config struct {
+		Upstreams []Upstream `env:"UPSTREAM"`
+	}
+
+	docs, err := gonfiguration.Describe(Config{})
+	require.NoError(t, err)
+	require.Equal(t, "environment:\n  UPSTREAM_0_HOST: \"${UPSTREAM_0_HOST:-localhost}\"\n", docs.ComposeEnvironment())
+	require.Equal(t, "env:\n  - name: UPSTREAM_0_HOST\n    value: \"localhost\"\n", docs.KubernetesEnv("s", ""))
+}
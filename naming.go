@@ -0,0 +1,58 @@
+package gonfiguration
+
+import (
+	"strings"
+	"unicode"
+)
+
+// NamingStrategy derives env keys for fields that don't spell one out.
+type NamingStrategy struct {
+	// FieldKey turns a Go field name into its env key.
+	FieldKey func(fieldName string) string
+	// Separator joins a nested struct's key to the keys of its fields.
+	Separator string
+}
+
+// ScreamingSnake derives HTTP_PORT from HTTPPort and nests with a single
+// underscore, so DB.Host reads DB_HOST.
+func ScreamingSnake() NamingStrategy {
+	return NamingStrategy{FieldKey: ScreamingSnakeCase, Separator: "_"}
+}
+
+// ScreamingSnakeNested is ScreamingSnake with a double underscore between
+// nesting levels, so DB.Host reads DB__HOST and can't be mistaken for a
+// top-level DBHost.
+func ScreamingSnakeNested() NamingStrategy {
+	return NamingStrategy{FieldKey: ScreamingSnakeCase, Separator: "__"}
+}
+
+// ScreamingSnakeCase converts a Go identifier to SCREAMING_SNAKE_CASE,
+// keeping acronyms together: HTTPPort becomes HTTP_PORT, UserID becomes
+// USER_ID and S3Bucket becomes S3_BUCKET.
+func ScreamingSnakeCase(name string) string {
+	runes := []rune(name)
+
+	var b strings.Builder
+
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) && startsWord(runes, i) {
+			b.WriteByte('_')
+		}
+
+		b.WriteRune(unicode.ToUpper(r))
+	}
+
+	return b.String()
+}
+
+// startsWord reports whether the upper-case rune at i begins a new word:
+// it follows a lower-case letter or digit, or it ends an acronym because
+// a lower-case letter comes next.
+func startsWord(runes []rune, i int) bool {
+	prev := runes[i-1]
+	if unicode.IsLower(prev) || unicode.IsDigit(prev) {
+		return true
+	}
+
+	return unicode.IsUpper(prev) && i+1 < len(runes) && unicode.IsLower(runes[i+1])
+}
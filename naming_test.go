@@ -0,0 +1,151 @@
+package gonfiguration_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/psyb0t/gonfiguration"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScreamingSnakeCase(t *testing.T) {
+	testCases := []struct {
+		name     string
+		expected string
+	}{
+		{name: "Port", expected: "PORT"},
+		{name: "ListenAddress", expected: "LISTEN_ADDRESS"},
+		{name: "HTTPPort", expected: "HTTP_PORT"},
+		{name: "UserID", expected: "USER_ID"},
+		{name: "APIKeyID", expected: "API_KEY_ID"},
+		{name: "S3Bucket", expected: "S3_BUCKET"},
+		{name: "Version2", expected: "VERSION2"},
+		{name: "DB", expected: "DB"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, gonfiguration.ScreamingSnakeCase(tc.name))
+		})
+	}
+}
+
+func TestParseWithAutoKeys(t *testing.T) {
+	type Database struct {
+		Host string
+		Port int `default:"5432"`
+	}
+
+	type Common struct {
+		LogLevel string `default:"info"`
+	}
+
+	type Config struct {
+		Common
+
+		HTTPPort    int
+		ReadTimeout time.Duration
+		Name        string `env:"APP_NAME"`
+		Token       string `env:",required"`
+		Ignored     string `env:"-"`
+		DB          Database
+		Replica     Database `env:"RO"`
+		internal    string
+	}
+
+	t.Run("derived keys", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		t.Setenv("HTTP_PORT", "8080")
+		t.Setenv("READ_TIMEOUT", "5s")
+		t.Setenv("APP_NAME", "svc")
+		t.Setenv("NAME", "ignored")
+		t.Setenv("TOKEN", "t0k3n")
+		t.Setenv("IGNORED", "ignored")
+		t.Setenv("DB_HOST", "db.internal")
+		t.Setenv("RO_HOST", "ro.internal")
+		t.Setenv("RO_PORT", "6432")
+		t.Setenv("LOG_LEVEL", "debug")
+
+		cfg := Config{}
+		require.NoError(t, gonfiguration.Parse(&cfg, gonfiguration.WithAutoKeys()))
+		require.Equal(t, 8080, cfg.HTTPPort)
+		require.Equal(t, 5*time.Second, cfg.ReadTimeout)
+		require.Equal(t, "svc", cfg.Name)
+		require.Equal(t, "t0k3n", cfg.Token)
+		require.Empty(t, cfg.Ignored)
+		require.Equal(t, Database{Host: "db.internal", Port: 5432}, cfg.DB)
+		require.Equal(t, Database{Host: "ro.internal", Port: 6432}, cfg.Replica)
+		require.Equal(t, "debug", cfg.LogLevel)
+		require.Empty(t, cfg.internal)
+	})
+
+	t.Run("derived required key", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		cfg := Config{}
+		err := gonfiguration.Parse(&cfg, gonfiguration.WithAutoKeys())
+		require.ErrorIs(t, err, gonfiguration.ErrRequiredFieldNotSet)
+		require.Contains(t, err.Error(), "field TOKEN")
+	})
+
+	t.Run("double underscore nesting", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		t.Setenv("TOKEN", "t0k3n")
+		t.Setenv("DB__HOST", "db.internal")
+		t.Setenv("DB_HOST", "wrong")
+
+		cfg := Config{}
+		require.NoError(t, gonfiguration.Parse(&cfg, gonfiguration.WithNaming(gonfiguration.ScreamingSnakeNested())))
+		require.Equal(t, "db.internal", cfg.DB.Host)
+	})
+
+	t.Run("custom strategy", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		type Small struct {
+			Port int
+		}
+
+		t.Setenv("port", "9090")
+
+		strategy := gonfiguration.NamingStrategy{
+			FieldKey:  strings.ToLower,
+			Separator: ".",
+		}
+
+		cfg := Small{}
+		require.NoError(t, gonfiguration.Parse(&cfg, gonfiguration.WithNaming(strategy)))
+		require.Equal(t, 9090, cfg.Port)
+	})
+
+	t.Run("untagged fields skipped without the option", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		type PlainConfig struct {
+			HTTPPort int
+			Name     string `env:"APP_NAME"`
+		}
+
+		t.Setenv("HTTP_PORT", "8080")
+		t.Setenv("APP_NAME", "svc")
+
+		cfg := PlainConfig{}
+		require.NoError(t, gonfiguration.Parse(&cfg))
+		require.Zero(t, cfg.HTTPPort)
+		require.Equal(t, "svc", cfg.Name)
+	})
+
+	t.Run("unsupported untagged field", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		type BadConfig struct {
+			Handler func()
+		}
+
+		cfg := BadConfig{}
+		require.ErrorIs(t, gonfiguration.Parse(&cfg, gonfiguration.WithAutoKeys()), gonfiguration.ErrUnsupportedFieldType)
+	})
+}
@@ -0,0 +1,135 @@
+package gonfiguration
+
+import (
+	"net"
+	"net/mail"
+	"net/netip"
+	"net/url"
+	"reflect"
+	"slices"
+
+	"github.com/psyb0t/ctxerrors"
+)
+
+func setURL(
+	fieldValue reflect.Value,
+	envVal string,
+	format valueFormat,
+) error {
+	u, err := url.Parse(envVal)
+	if err != nil {
+		return ctxerrors.Wrapf(ErrInvalidURL, "expected URL: %v", err)
+	}
+
+	if len(format.schemes) > 0 && !slices.Contains(format.schemes, u.Scheme) {
+		return ctxerrors.Wrapf(
+			ErrURLSchemeNotAllowed,
+			"scheme %q not in %v", u.Scheme, format.schemes,
+		)
+	}
+
+	setPointerOrValue(fieldValue, u)
+
+	return nil
+}
+
+func setIP(
+	fieldValue reflect.Value,
+	envVal string,
+	_ valueFormat,
+) error {
+	ip := net.ParseIP(envVal)
+	if ip == nil {
+		return ctxerrors.Wrapf(ErrInvalidAddress, "expected IP address, got %q", envVal)
+	}
+
+	fieldValue.Set(reflect.ValueOf(ip))
+
+	return nil
+}
+
+func setIPNet(
+	fieldValue reflect.Value,
+	envVal string,
+	_ valueFormat,
+) error {
+	_, ipNet, err := net.ParseCIDR(envVal)
+	if err != nil {
+		return ctxerrors.Wrapf(ErrInvalidAddress, "expected CIDR network: %v", err)
+	}
+
+	setPointerOrValue(fieldValue, ipNet)
+
+	return nil
+}
+
+func setNetipAddr(
+	fieldValue reflect.Value,
+	envVal string,
+	_ valueFormat,
+) error {
+	addr, err := netip.ParseAddr(envVal)
+	if err != nil {
+		return ctxerrors.Wrapf(ErrInvalidAddress, "expected IP address: %v", err)
+	}
+
+	fieldValue.Set(reflect.ValueOf(addr))
+
+	return nil
+}
+
+func setNetipAddrPort(
+	fieldValue reflect.Value,
+	envVal string,
+	_ valueFormat,
+) error {
+	addrPort, err := netip.ParseAddrPort(envVal)
+	if err != nil {
+		return ctxerrors.Wrapf(ErrInvalidAddress, "expected IP:port address: %v", err)
+	}
+
+	fieldValue.Set(reflect.ValueOf(addrPort))
+
+	return nil
+}
+
+func setNetipPrefix(
+	fieldValue reflect.Value,
+	envVal string,
+	_ valueFormat,
+) error {
+	prefix, err := netip.ParsePrefix(envVal)
+	if err != nil {
+		return ctxerrors.Wrapf(ErrInvalidAddress, "expected IP prefix: %v", err)
+	}
+
+	fieldValue.Set(reflect.ValueOf(prefix))
+
+	return nil
+}
+
+func setMailAddress(
+	fieldValue reflect.Value,
+	envVal string,
+	_ valueFormat,
+) error {
+	addr, err := mail.ParseAddress(envVal)
+	if err != nil {
+		return ctxerrors.Wrapf(ErrInvalidAddress, "expected email address: %v", err)
+	}
+
+	setPointerOrValue(fieldValue, addr)
+
+	return nil
+}
+
+// setPointerOrValue stores ptr in fieldValue, dereferencing it first when
+// the field holds the value type rather than the pointer.
+func setPointerOrValue(fieldValue reflect.Value, ptr any) {
+	val := reflect.ValueOf(ptr)
+	if fieldValue.Kind() != reflect.Pointer {
+		val = val.Elem()
+	}
+
+	fieldValue.Set(val)
+}
@@ -0,0 +1,230 @@
+package gonfiguration_test
+
+import (
+	"net"
+	"net/mail"
+	"net/netip"
+	"net/url"
+	"testing"
+
+	"github.com/psyb0t/gonfiguration"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseNetworkTypes(t *testing.T) {
+	defer gonfiguration.Reset()
+
+	type Config struct {
+		Endpoint   url.URL        `env:"ENDPOINT"`
+		Callback   *url.URL       `env:"CALLBACK"`
+		BindIP     net.IP         `env:"BIND_IP"`
+		Subnet     net.IPNet      `env:"SUBNET"`
+		Trusted    *net.IPNet     `env:"TRUSTED"`
+		Addr       netip.Addr     `env:"ADDR"`
+		Listen     netip.AddrPort `env:"LISTEN"`
+		Prefix     netip.Prefix   `env:"PREFIX"`
+		Admin      mail.Address   `env:"ADMIN"`
+		ReplyTo    *mail.Address  `env:"REPLY_TO"`
+		Peers      []netip.Addr   `env:"PEERS"`
+		AllowedIPs []net.IP       `env:"ALLOWED_IPS"`
+		Mirrors    []*url.URL     `env:"MIRRORS"`
+	}
+
+	t.Setenv("ENDPOINT", "https://api.example.com/v1")
+	t.Setenv("CALLBACK", "http://localhost:8080/cb?x=1")
+	t.Setenv("BIND_IP", "10.0.0.1")
+	t.Setenv("SUBNET", "10.1.2.3/16")
+	t.Setenv("TRUSTED", "fd00::/8")
+	t.Setenv("ADDR", "::1")
+	t.Setenv("LISTEN", "127.0.0.1:9090")
+	t.Setenv("PREFIX", "192.168.0.0/24")
+	t.Setenv("ADMIN", "Ops Team <ops@example.com>")
+	t.Setenv("REPLY_TO", "noreply@example.com")
+	t.Setenv("PEERS", "10.0.0.2, 10.0.0.3")
+	t.Setenv("ALLOWED_IPS", "127.0.0.1,::1")
+	t.Setenv("MIRRORS", "https://a.example.com,https://b.example.com")
+
+	cfg := Config{}
+	require.NoError(t, gonfiguration.Parse(&cfg))
+
+	require.Equal(t, "api.example.com", cfg.Endpoint.Host)
+	require.Equal(t, "/v1", cfg.Endpoint.Path)
+	require.Equal(t, "1", cfg.Callback.Query().Get("x"))
+	require.True(t, net.ParseIP("10.0.0.1").Equal(cfg.BindIP))
+	require.Equal(t, "10.1.0.0/16", cfg.Subnet.String())
+	require.Equal(t, "fd00::/8", cfg.Trusted.String())
+	require.Equal(t, netip.MustParseAddr("::1"), cfg.Addr)
+	require.Equal(t, netip.MustParseAddrPort("127.0.0.1:9090"), cfg.Listen)
+	require.Equal(t, netip.MustParsePrefix("192.168.0.0/24"), cfg.Prefix)
+	require.Equal(t, mail.Address{Name: "Ops Team", Address: "ops@example.com"}, cfg.Admin)
+	require.Equal(t, "noreply@example.com", cfg.ReplyTo.Address)
+	require.Equal(t, []netip.Addr{
+		netip.MustParseAddr("10.0.0.2"),
+		netip.MustParseAddr("10.0.0.3"),
+	}, cfg.Peers)
+	require.Len(t, cfg.AllowedIPs, 2)
+	require.True(t, net.IPv6loopback.Equal(cfg.AllowedIPs[1]))
+	require.Len(t, cfg.Mirrors, 2)
+	require.Equal(t, "b.example.com", cfg.Mirrors[1].Host)
+}
+
+func TestParseNetworkTypeErrors(t *testing.T) {
+	testCases := []struct {
+		name    string
+		dst     any
+		key     string
+		value   string
+		errorIs error
+	}{
+		{
+			name: "invalid ip",
+			dst: &struct {
+				IP net.IP `env:"BIND_IP"`
+			}{},
+			key:     "BIND_IP",
+			value:   "10.0.0.300",
+			errorIs: gonfiguration.ErrInvalidAddress,
+		},
+		{
+			name: "invalid cidr",
+			dst: &struct {
+				Net *net.IPNet `env:"SUBNET"`
+			}{},
+			key:     "SUBNET",
+			value:   "10.0.0.0",
+			errorIs: gonfiguration.ErrInvalidAddress,
+		},
+		{
+			name: "invalid netip addr",
+			dst: &struct {
+				Addr netip.Addr `env:"ADDR"`
+			}{},
+			key:     "ADDR",
+			value:   "localhost",
+			errorIs: gonfiguration.ErrInvalidAddress,
+		},
+		{
+			name: "addr port without port",
+			dst: &struct {
+				Listen netip.AddrPort `env:"LISTEN"`
+			}{},
+			key:     "LISTEN",
+			value:   "127.0.0.1",
+			errorIs: gonfiguration.ErrInvalidAddress,
+		},
+		{
+			name: "invalid prefix",
+			dst: &struct {
+				Prefix netip.Prefix `env:"PREFIX"`
+			}{},
+			key:     "PREFIX",
+			value:   "10.0.0.0/33",
+			errorIs: gonfiguration.ErrInvalidAddress,
+		},
+		{
+			name: "invalid mail address",
+			dst: &struct {
+				Admin mail.Address `env:"ADMIN"`
+			}{},
+			key:     "ADMIN",
+			value:   "not an email",
+			errorIs: gonfiguration.ErrInvalidAddress,
+		},
+		{
+			name: "invalid url",
+			dst: &struct {
+				Endpoint *url.URL `env:"ENDPOINT"`
+			}{},
+			key:     "ENDPOINT",
+			value:   "http://[::1",
+			errorIs: gonfiguration.ErrInvalidURL,
+		},
+		{
+			name: "invalid item in slice",
+			dst: &struct {
+				Peers []netip.Addr `env:"PEERS"`
+			}{},
+			key:     "PEERS",
+			value:   "10.0.0.1,nope",
+			errorIs: gonfiguration.ErrInvalidAddress,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			defer gonfiguration.Reset()
+
+			t.Setenv(tc.key, tc.value)
+
+			err := gonfiguration.Parse(tc.dst)
+			require.ErrorIs(t, err, tc.errorIs)
+			require.Contains(t, err.Error(), "field "+tc.key)
+		})
+	}
+}
+
+func TestParseURLScheme(t *testing.T) {
+	type Config struct {
+		Endpoint *url.URL `env:"ENDPOINT" scheme:"http, HTTPS"`
+	}
+
+	t.Run("allowed scheme", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		t.Setenv("ENDPOINT", "HTTPS://example.com")
+
+		cfg := Config{}
+		require.NoError(t, gonfiguration.Parse(&cfg))
+		require.Equal(t, "https", cfg.Endpoint.Scheme)
+	})
+
+	t.Run("disallowed scheme", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		t.Setenv("ENDPOINT", "ftp://example.com")
+
+		cfg := Config{}
+		err := gonfiguration.Parse(&cfg)
+		require.ErrorIs(t, err, gonfiguration.ErrURLSchemeNotAllowed)
+		require.Contains(t, err.Error(), "field ENDPOINT")
+	})
+
+	t.Run("missing scheme", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		t.Setenv("ENDPOINT", "example.com/path")
+
+		cfg := Config{}
+		require.ErrorIs(t, gonfiguration.Parse(&cfg), gonfiguration.ErrURLSchemeNotAllowed)
+	})
+
+	t.Run("tag default checked too", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		type DefaultConfig struct {
+			Endpoint *url.URL `env:"ENDPOINT" scheme:"https" default:"http://example.com"`
+		}
+
+		cfg := DefaultConfig{}
+		require.ErrorIs(t, gonfiguration.Parse(&cfg), gonfiguration.ErrURLSchemeNotAllowed)
+	})
+}
+
+func TestParseGenericSlices(t *testing.T) {
+	defer gonfiguration.Reset()
+
+	type Config struct {
+		Ports  []int     `env:"PORTS"`
+		Ratios []float64 `env:"RATIOS" default:"0.5,1.5"`
+		Flags  []bool    `env:"FLAGS"`
+	}
+
+	t.Setenv("PORTS", "80, 443")
+	t.Setenv("FLAGS", "")
+
+	cfg := Config{}
+	require.NoError(t, gonfiguration.Parse(&cfg))
+	require.Equal(t, []int{80, 443}, cfg.Ports)
+	require.Equal(t, []float64{0.5, 1.5}, cfg.Ratios)
+	require.Equal(t, []bool{}, cfg.Flags)
+}
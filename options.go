@@ -0,0 +1,136 @@
+package gonfiguration
+
+import "log/slog"
+
+// Option changes how Parse reads the environment into a struct.
+type Option func(*options)
+
+type options struct {
+	naming *NamingStrategy
+	prefix string
+	logger *slog.Logger
+
+	caseInsensitive bool
+	strict          bool
+	strictPrefix    string
+	consumedOnly    bool
+	revealSecrets   bool
+
+	// environ replaces the process environment when hasEnviron is set.
+	environ    []string
+	hasEnviron bool
+
+	// report collects field provenance for Explain.
+	report *Report
+}
+
+func newOptions(opts []Option) options {
+	parsed := options{}
+
+	for _, opt := range opts {
+		opt(&parsed)
+	}
+
+	return parsed
+}
+
+// envVars returns the env snapshot to parse: the WithEnviron lists if
+// any were given, the process environment otherwise.
+func (o options) envVars() (map[string]string, error) {
+	if o.hasEnviron {
+		return parseEnviron(o.environ)
+	}
+
+	return getEnvVars()
+}
+
+// WithNaming derives the key of every exported field without an env tag
+// using strategy. Fields with an explicit key keep it and env:"-" still
+// excludes a field. Plain struct fields are parsed as nested structs,
+// their key joined to their fields' keys with the strategy's separator.
+func WithNaming(strategy NamingStrategy) Option {
+	return func(o *options) {
+		o.naming = &strategy
+	}
+}
+
+// WithAutoKeys is WithNaming(ScreamingSnake()): HTTPPort reads HTTP_PORT.
+func WithAutoKeys() Option {
+	return WithNaming(ScreamingSnake())
+}
+
+// WithPrefix prepends prefix to every key Parse reads, including derived,
+// nested, indexed and tlskey keys, so one struct type can be parsed for
+// several apps: WithPrefix("APP1_") reads APP1_PORT for env:"PORT". The
+// prefix is used as given, separator included. Programmatic defaults and
+// errors use the prefixed key.
+func WithPrefix(prefix string) Option {
+	return func(o *options) {
+		o.prefix = prefix
+	}
+}
+
+// WithLogger sets the logger warnings such as deprecated keys go to. It
+// defaults to slog.Default().
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
+
+// WithCaseInsensitiveKeys matches env var names regardless of case, so
+// db_url and Db_Url both satisfy env:"DB_URL". Differently-cased variants
+// of a key that a field reads must hold the same value, otherwise Parse
+// fails with ErrAmbiguousKey rather than picking one of them.
+func WithCaseInsensitiveKeys() Option {
+	return func(o *options) {
+		o.caseInsensitive = true
+	}
+}
+
+// WithStrict fails Parse with ErrUnknownKey for every env var starting
+// with prefix that no field reads, so a typo like MYAPP_DB_PSSWORD is an
+// error instead of a silently ignored variable. Each one comes with the
+// closest key the destination does read, if there is a close one.
+func WithStrict(prefix string) Option {
+	return func(o *options) {
+		o.strict = true
+		o.strictPrefix = prefix
+	}
+}
+
+// WithConsumedKeysOnly caches only the env vars that fields of dst read,
+// instead of the whole environment, for GetEnvVars and GetAllValues. A
+// key a field reads that is no longer set is dropped from the cache.
+func WithConsumedKeysOnly() Option {
+	return func(o *options) {
+		o.consumedOnly = true
+	}
+}
+
+// WithEnviron makes Parse, Explain and Check read environ, KEY=VALUE
+// strings like os.Environ returns, instead of the process environment.
+// Passing it more than once layers the lists, later values winning.
+func WithEnviron(environ []string) Option {
+	return func(o *options) {
+		o.environ = append(o.environ, environ...)
+		o.hasEnviron = true
+	}
+}
+
+// WithSecretsRevealed makes the Marshal functions write secret values as
+// they are instead of [REDACTED], e.g. to build a child process's
+// environment.
+func WithSecretsRevealed() Option {
+	return func(o *options) {
+		o.revealSecrets = true
+	}
+}
+
+func (p *parser) logger() *slog.Logger {
+	if p.options.logger == nil {
+		return slog.Default()
+	}
+
+	return p.options.logger
+}
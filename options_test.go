@@ -0,0 +1,163 @@
+package gonfiguration_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/psyb0t/gonfiguration"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseWithPrefix(t *testing.T) {
+	type Backend struct {
+		Host string `env:"HOST"`
+	}
+
+	type Config struct {
+		Port     int       `env:"PORT" default:"80"`
+		Name     string    `env:"NAME,required"`
+		Backends []Backend `env:"BACKEND"`
+	}
+
+	t.Run("same struct for two apps", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		t.Setenv("APP1_PORT", "8081")
+		t.Setenv("APP1_NAME", "one")
+		t.Setenv("APP1_BACKEND_0_HOST", "a.internal")
+		t.Setenv("APP2_NAME", "two")
+		t.Setenv("PORT", "9999")
+
+		app1 := Config{}
+		require.NoError(t, gonfiguration.Parse(&app1, gonfiguration.WithPrefix("APP1_")))
+		require.Equal(t, Config{Port: 8081, Name: "one", Backends: []Backend{{Host: "a.internal"}}}, app1)
+
+		app2 := Config{}
+		require.NoError(t, gonfiguration.Parse(&app2, gonfiguration.WithPrefix("APP2_")))
+		require.Equal(t, Config{Port: 80, Name: "two"}, app2)
+	})
+
+	t.Run("derived and nested keys", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		type Database struct {
+			Host string
+		}
+
+		type AutoConfig struct {
+			HTTPPort int
+			DB       Database
+		}
+
+		t.Setenv("APP1_HTTP_PORT", "8080")
+		t.Setenv("APP1_DB_HOST", "db.internal")
+
+		cfg := AutoConfig{}
+		require.NoError(t, gonfiguration.Parse(&cfg, gonfiguration.WithPrefix("APP1_"), gonfiguration.WithAutoKeys()))
+		require.Equal(t, 8080, cfg.HTTPPort)
+		require.Equal(t, "db.internal", cfg.DB.Host)
+	})
+
+	t.Run("programmatic defaults and errors use the prefixed key", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		gonfiguration.SetDefault("APP1_PORT", 7000)
+
+		cfg := Config{}
+		err := gonfiguration.Parse(&cfg, gonfiguration.WithPrefix("APP1_"))
+		require.ErrorIs(t, err, gonfiguration.ErrRequiredFieldNotSet)
+		require.Contains(t, err.Error(), "field APP1_NAME")
+		require.Equal(t, 7000, cfg.Port)
+	})
+}
+
+func TestParseWithConsumedKeysOnly(t *testing.T) {
+	type Config struct {
+		Port  int    `env:"PORT"`
+		Token string `env:"TOKEN"`
+	}
+
+	t.Run("only consumed keys cached", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		t.Setenv("PORT", "8080")
+		t.Setenv("CLOUD_SECRET_KEY", "do-not-keep")
+
+		cfg := Config{}
+		require.NoError(t, gonfiguration.Parse(&cfg, gonfiguration.WithConsumedKeysOnly()))
+		require.Equal(t, map[string]string{"PORT": "8080"}, gonfiguration.GetEnvVars())
+		require.NotContains(t, gonfiguration.GetAllValues(), "CLOUD_SECRET_KEY")
+	})
+
+	t.Run("keys gone between parses are dropped", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		t.Setenv("PORT", "8080")
+		t.Setenv("TOKEN", "t0k3n")
+
+		cfg := Config{}
+		require.NoError(t, gonfiguration.Parse(&cfg, gonfiguration.WithConsumedKeysOnly()))
+		require.Contains(t, gonfiguration.GetEnvVars(), "TOKEN")
+
+		require.NoError(t, os.Unsetenv("TOKEN"))
+		t.Setenv("PORT", "9090")
+
+		cfg = Config{}
+		require.NoError(t, gonfiguration.Parse(&cfg, gonfiguration.WithConsumedKeysOnly()))
+		require.Equal(t, map[string]string{"PORT": "9090"}, gonfiguration.GetEnvVars())
+	})
+
+	t.Run("keys of shrunk indexed slices are dropped", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		type Upstream struct {
+			Host string `env:"HOST"`
+		}
+
+		type UpstreamConfig struct {
+			Upstreams []Upstream          `env:"UP"`
+			Named     map[string]Upstream `env:"NAMED"`
+		}
+
+		t.Setenv("UP_0_HOST", "a")
+		t.Setenv("UP_1_HOST", "b")
+		t.Setenv("NAMED_EU_HOST", "eu")
+		t.Setenv("NAMED_US_HOST", "us")
+
+		require.NoError(t, gonfiguration.Parse(&UpstreamConfig{}, gonfiguration.WithConsumedKeysOnly()))
+		require.Contains(t, gonfiguration.GetEnvVars(), "UP_1_HOST")
+
+		require.NoError(t, os.Unsetenv("UP_1_HOST"))
+		require.NoError(t, os.Unsetenv("NAMED_US_HOST"))
+
+		require.NoError(t, gonfiguration.Parse(&UpstreamConfig{}, gonfiguration.WithConsumedKeysOnly()))
+		require.Equal(t, map[string]string{
+			"UP_0_HOST":     "a",
+			"NAMED_EU_HOST": "eu",
+		}, gonfiguration.GetEnvVars())
+	})
+
+	t.Run("keys of other structs kept", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		type Other struct {
+			Name string `env:"NAME"`
+		}
+
+		t.Setenv("PORT", "8080")
+		t.Setenv("NAME", "svc")
+
+		require.NoError(t, gonfiguration.Parse(&Config{}, gonfiguration.WithConsumedKeysOnly()))
+		require.NoError(t, gonfiguration.Parse(&Other{}, gonfiguration.WithConsumedKeysOnly()))
+		require.Equal(t, map[string]string{"PORT": "8080", "NAME": "svc"}, gonfiguration.GetEnvVars())
+	})
+
+	t.Run("whole environment cached by default", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		t.Setenv("CLOUD_SECRET_KEY", "kept")
+
+		require.NoError(t, gonfiguration.Parse(&Config{}))
+		require.Contains(t, gonfiguration.GetEnvVars(), "CLOUD_SECRET_KEY")
+	})
+}
@@ -0,0 +1,50 @@
+package gonfiguration
+
+import (
+	"reflect"
+	"regexp"
+	"text/template"
+
+	"github.com/psyb0t/ctxerrors"
+)
+
+func setRegexp(
+	fieldValue reflect.Value,
+	envVal string,
+	_ valueFormat,
+) error {
+	re, err := regexp.Compile(envVal)
+	if err != nil {
+		return ctxerrors.Wrap(err, "failed to compile regexp")
+	}
+
+	fieldValue.Set(reflect.ValueOf(re))
+
+	return nil
+}
+
+func setTemplate(
+	fieldValue reflect.Value,
+	envVal string,
+	_ valueFormat,
+) error {
+	tmpl, err := template.New("").Parse(envVal)
+	if err != nil {
+		return ctxerrors.Wrap(err, "failed to parse template")
+	}
+
+	fieldValue.Set(reflect.ValueOf(tmpl))
+
+	return nil
+}
+
+// formatTemplate prints the parsed template back as template text, which
+// parses to the same template even if spacing inside actions differs.
+func formatTemplate(v reflect.Value, _ valueFormat) (string, error) {
+	tmpl, _ := v.Interface().(*template.Template)
+	if tmpl.Tree == nil {
+		return "", nil
+	}
+
+	return tmpl.Root.String(), nil
+}
@@ -0,0 +1,82 @@
+package gonfiguration_test
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+	"text/template"
+
+	"github.com/psyb0t/gonfiguration"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRegexp(t *testing.T) {
+	t.Run("compiled at parse time", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		type Config struct {
+			Route   *regexp.Regexp   `env:"ROUTE"`
+			Allow   []*regexp.Regexp `env:"ALLOW"`
+			Default *regexp.Regexp   `env:"DEFAULT_ROUTE" default:"^/health$"`
+		}
+
+		t.Setenv("ROUTE", `^/api/v(\d+)/`)
+		t.Setenv("ALLOW", `^10\., ^192\.168\.`)
+
+		cfg := Config{}
+		require.NoError(t, gonfiguration.Parse(&cfg))
+		require.Equal(t, []string{"/api/v2/", "2"}, cfg.Route.FindStringSubmatch("/api/v2/users"))
+		require.Len(t, cfg.Allow, 2)
+		require.True(t, cfg.Allow[1].MatchString("192.168.1.1"))
+		require.False(t, cfg.Allow[0].MatchString("192.168.1.1"))
+		require.True(t, cfg.Default.MatchString("/health"))
+	})
+
+	t.Run("bad pattern names the key", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		type Config struct {
+			Deny []*regexp.Regexp `env:"DENY"`
+		}
+
+		t.Setenv("DENY", `^ok$,(unclosed`)
+
+		cfg := Config{}
+		err := gonfiguration.Parse(&cfg)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "field DENY")
+		require.Contains(t, err.Error(), "missing closing )")
+	})
+}
+
+func TestParseTemplate(t *testing.T) {
+	t.Run("parsed at parse time", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		type Config struct {
+			Greeting *template.Template `env:"GREETING" default:"hello {{.}}"`
+		}
+
+		cfg := Config{}
+		require.NoError(t, gonfiguration.Parse(&cfg))
+
+		buf := bytes.Buffer{}
+		require.NoError(t, cfg.Greeting.Execute(&buf, "world"))
+		require.Equal(t, "hello world", buf.String())
+	})
+
+	t.Run("bad template names the key", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		type Config struct {
+			Subject *template.Template `env:"SUBJECT"`
+		}
+
+		t.Setenv("SUBJECT", "{{.Name")
+
+		cfg := Config{}
+		err := gonfiguration.Parse(&cfg)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "field SUBJECT")
+	})
+}
@@ -0,0 +1,333 @@
+package gonfiguration
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"net/mail"
+	"net/netip"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+	"unicode"
+
+	"github.com/psyb0t/ctxerrors"
+)
+
+const (
+	jsonSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+	// goDurationPattern matches what time.ParseDuration accepts.
+	goDurationNumber  = `([0-9]+(\.[0-9]*)?|\.[0-9]+)`
+	goDurationPattern = `^[-+]?(0|(` + goDurationNumber + `(ns|us|µs|μs|ms|s|m|h))+)$`
+
+	// extendedDurationPattern adds the d and w units and ISO-8601
+	// durations accepted by parseExtendedDuration.
+	isoDurationNumber       = `[0-9]+([.,][0-9]*)?`
+	extendedDurationPattern = `^[-+]?((` + goDurationNumber + `(ns|us|µs|μs|ms|s|m|h|d|w))+|0|` +
+		`[Pp]((` + isoDurationNumber + `[WwDd])+([Tt](` + isoDurationNumber + `[HhMmSs])+)?|` +
+		`[Tt](` + isoDurationNumber + `[HhMmSs])+))$`
+
+	unixTimePattern = `^[-+]?[0-9]+$`
+	hexPattern      = `^([0-9a-fA-F]{2})*$`
+
+	// indexPattern and namePattern replace the <N> and <NAME>
+	// placeholders in the patternProperties of slices and maps of structs.
+	indexPattern = "[0-9]+"
+	namePattern  = ".+"
+)
+
+// JSONSchema returns a JSON Schema (draft 2020-12) describing the env
+// vars dst's type reads with the given options, as the flat object
+// MarshalJSON and MarshalYAML write: one property per key, typed and
+// constrained the way setEnvVarValue parses it (integer ranges from the
+// field's bit size, oneof enums, date-time, uri and ip formats, duration
+// patterns), with defaults, descriptions and the required keys. Keys of
+// slices and maps of structs become patternProperties. Secret defaults
+// are left out and secret properties are marked writeOnly.
+//
+// JSON Schema's "duration" format is ISO-8601 only, so time.Duration
+// fields get a pattern for the format they actually accept instead.
+func JSONSchema(dst any, opts ...Option) ([]byte, error) {
+	vars, err := describeVars(dst, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	properties := map[string]any{}
+	patternProperties := map[string]any{}
+	required := []string{}
+
+	for _, v := range vars {
+		pattern, isPattern := keyPattern(v.Key)
+		if isPattern {
+			patternProperties[pattern] = v.schema()
+
+			continue
+		}
+
+		properties[v.Key] = v.schema()
+
+		if v.Required && !v.HasDefault {
+			required = append(required, v.Key)
+		}
+	}
+
+	schema := map[string]any{
+		"$schema":    jsonSchemaDraft,
+		"type":       "object",
+		"properties": properties,
+		"required":   required,
+	}
+
+	if len(patternProperties) > 0 {
+		schema["patternProperties"] = patternProperties
+	}
+
+	b, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return nil, ctxerrors.Wrap(err, "failed to encode JSON schema")
+	}
+
+	return append(b, '\n'), nil
+}
+
+// keyPattern turns a key with <N> or <NAME> placeholders into an
+// anchored regular expression.
+func keyPattern(key string) (string, bool) {
+	if !strings.Contains(key, indexPlaceholder) && !strings.Contains(key, namePlaceholder) {
+		return "", false
+	}
+
+	pattern := strings.NewReplacer(
+		regexp.QuoteMeta(indexPlaceholder), indexPattern,
+		regexp.QuoteMeta(namePlaceholder), namePattern,
+	).Replace(regexp.QuoteMeta(key))
+
+	return "^" + pattern + "$", true
+}
+
+func (v describedVar) schema() map[string]any {
+	format := valueFormatFromField(v.field)
+	format.json = v.tag.json
+	schema := typeSchema(v.typ, format)
+
+	if v.Description != "" {
+		schema["description"] = v.Description
+	}
+
+	if v.Deprecated && len(v.Aliases) > 0 {
+		schema["$comment"] = "Deprecated aliases: " + strings.Join(v.Aliases, ", ")
+	}
+
+	if v.Secret {
+		schema["writeOnly"] = true
+	} else if v.HasDefault {
+		if val, ok := schemaValue(v.typ, format, v.Default); ok {
+			schema["default"] = val
+		}
+	}
+
+	if len(format.oneOf) > 0 {
+		target := schema
+		elemType := v.typ
+
+		if isDelimitedList(v.typ) {
+			target, _ = schema["items"].(map[string]any)
+			elemType = v.typ.Elem()
+		}
+
+		enum := []any{}
+
+		for _, allowed := range format.oneOf {
+			if val, ok := schemaValue(elemType, format, allowed); ok {
+				enum = append(enum, val)
+			}
+		}
+
+		target["enum"] = enum
+	}
+
+	return schema
+}
+
+// unparsedSchemaTypes are the types whose setters read a PEM file or
+// the zone database. Their defaults go into a schema as written, so
+// exporting one never touches the filesystem. A certificate with its key
+// inline is secret, so its default never gets this far.
+//
+//nolint:gochecknoglobals
+var unparsedSchemaTypes = map[reflect.Type]struct{}{
+	reflect.TypeFor[tls.Certificate](): {},
+	reflect.TypeFor[*x509.CertPool]():  {},
+	reflect.TypeFor[*time.Location]():  {},
+}
+
+// schemaValue parses val the way Parse would and returns it as it
+// appears in MarshalJSON output. Values of unparsedSchemaTypes are only
+// split into items for lists.
+func schemaValue(typ reflect.Type, format valueFormat, val string) (any, bool) {
+	if _, ok := unparsedSchemaTypes[typ]; ok {
+		return val, true
+	}
+
+	if isDelimitedList(typ) {
+		if _, ok := unparsedSchemaTypes[typ.Elem()]; ok {
+			items, err := splitList(val, format)
+			if err != nil {
+				return nil, false
+			}
+
+			typed := make([]any, len(items))
+			for i, item := range items {
+				typed[i] = item
+			}
+
+			return typed, true
+		}
+	}
+
+	fieldValue := reflect.New(typ).Elem()
+
+	if err := setEnvVarValue(fieldValue, val, format); err != nil {
+		return nil, false
+	}
+
+	return typedValue(fieldValue, format, val), true
+}
+
+//nolint:cyclop
+func typeSchema(typ reflect.Type, format valueFormat) map[string]any {
+	if format.json {
+		return map[string]any{"type": "string", "contentMediaType": "application/json"}
+	}
+
+	switch typ {
+	case reflect.TypeFor[time.Duration]():
+		pattern := goDurationPattern
+		if format.extendedDuration {
+			pattern = extendedDurationPattern
+		}
+
+		return map[string]any{"type": "string", "pattern": pattern}
+	case reflect.TypeFor[time.Time]():
+		return timeSchema(format.layout)
+	case reflect.TypeFor[url.URL](), reflect.TypeFor[*url.URL]():
+		return urlSchema(format.schemes)
+	case reflect.TypeFor[net.IP](), reflect.TypeFor[netip.Addr]():
+		return map[string]any{
+			"type":  "string",
+			"anyOf": []any{map[string]any{"format": "ipv4"}, map[string]any{"format": "ipv6"}},
+		}
+	case reflect.TypeFor[*time.Location](),
+		reflect.TypeFor[net.IPNet](), reflect.TypeFor[*net.IPNet](),
+		reflect.TypeFor[netip.AddrPort](), reflect.TypeFor[netip.Prefix](),
+		reflect.TypeFor[mail.Address](), reflect.TypeFor[*mail.Address](),
+		reflect.TypeFor[*regexp.Regexp](), reflect.TypeFor[*template.Template](),
+		reflect.TypeFor[tls.Certificate](), reflect.TypeFor[*x509.CertPool]():
+		return map[string]any{"type": "string"}
+	}
+
+	if isByteSequence(typ) {
+		return bytesSchema(typ, format.encoding)
+	}
+
+	switch typ.Kind() { //nolint:exhaustive
+	case reflect.Slice, reflect.Array:
+		schema := map[string]any{"type": "array", "items": typeSchema(typ.Elem(), format)}
+		if typ.Kind() == reflect.Array {
+			schema["minItems"], schema["maxItems"] = typ.Len(), typ.Len()
+		}
+
+		return schema
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return map[string]any{
+			"type":    "integer",
+			"minimum": int64(-1) << (typ.Bits() - 1),
+			"maximum": int64(uint64(1)<<(typ.Bits()-1) - 1),
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{
+			"type":    "integer",
+			"minimum": 0,
+			"maximum": uint64(math.MaxUint64) >> (64 - typ.Bits()), //nolint:mnd
+		}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	default:
+		return map[string]any{"type": "string"}
+	}
+}
+
+func timeSchema(layout string) map[string]any {
+	switch layout {
+	case "":
+		return map[string]any{"type": "string", "format": "date-time"}
+	case layoutUnix, layoutUnixMilli:
+		return map[string]any{"type": "string", "pattern": unixTimePattern}
+	default:
+		return map[string]any{"type": "string"}
+	}
+}
+
+func urlSchema(schemes []string) map[string]any {
+	if len(schemes) == 0 {
+		return map[string]any{"type": "string", "format": "uri-reference"}
+	}
+
+	alternatives := make([]string, len(schemes))
+	for i, scheme := range schemes {
+		alternatives[i] = caseInsensitivePattern(scheme)
+	}
+
+	return map[string]any{
+		"type":    "string",
+		"format":  "uri",
+		"pattern": "^(" + strings.Join(alternatives, "|") + "):",
+	}
+}
+
+func bytesSchema(typ reflect.Type, encoding string) map[string]any {
+	schema := map[string]any{"type": "string"}
+
+	switch encoding {
+	case encodingBase64:
+		schema["contentEncoding"] = "base64"
+	case encodingBase64URL:
+		schema["contentEncoding"] = "base64url"
+	case encodingHex:
+		schema["pattern"] = hexPattern
+		if typ.Kind() == reflect.Array {
+			schema["pattern"] = fmt.Sprintf("^[0-9a-fA-F]{%d}$", 2*typ.Len()) //nolint:mnd
+		}
+	}
+
+	return schema
+}
+
+// caseInsensitivePattern matches s in any case, e.g. "[hH][tT]", since
+// JSON Schema patterns have no case-insensitive flag.
+func caseInsensitivePattern(s string) string {
+	var b strings.Builder
+
+	for _, r := range s {
+		lower, upper := unicode.ToLower(r), unicode.ToUpper(r)
+		if lower == upper {
+			b.WriteString(regexp.QuoteMeta(string(r)))
+
+			continue
+		}
+
+		b.WriteString("[" + string(lower) + string(upper) + "]")
+	}
+
+	return b.String()
+}
@@ -0,0 +1,126 @@
+package gonfiguration
+
+import (
+	"fmt"
+	"log/slog"
+	"reflect"
+	"strings"
+)
+
+// redacted replaces secret values wherever they'd otherwise be shown.
+const redacted = "[REDACTED]"
+
+// Secret holds a config value that must not leak into logs. It's parsed
+// like a T, its key is treated as secret, and it prints as [REDACTED]
+// through fmt, log/slog and encoding/json. Value returns the real thing.
+type Secret[T any] struct {
+	value T
+}
+
+// NewSecret wraps val, e.g. for SetDefault or tests.
+func NewSecret[T any](val T) Secret[T] {
+	return Secret[T]{value: val}
+}
+
+// Value returns the secret value.
+func (s Secret[T]) Value() T {
+	return s.value
+}
+
+func (s Secret[T]) String() string {
+	return redacted
+}
+
+// Format prints the mask for every verb, %#v and %d included.
+func (s Secret[T]) Format(f fmt.State, _ rune) {
+	_, _ = fmt.Fprint(f, redacted)
+}
+
+func (s Secret[T]) LogValue() slog.Value {
+	return slog.StringValue(redacted)
+}
+
+func (s Secret[T]) MarshalText() ([]byte, error) {
+	return []byte(redacted), nil
+}
+
+func (s *Secret[T]) secretValue() reflect.Value {
+	return reflect.ValueOf(&s.value).Elem()
+}
+
+// secretHolder is implemented by *Secret[T] for every T.
+type secretHolder interface {
+	secretValue() reflect.Value
+}
+
+// unwrapSecret returns the settable value inside a Secret[T] field.
+func unwrapSecret(fieldValue reflect.Value) (reflect.Value, bool) {
+	if !fieldValue.CanAddr() {
+		return reflect.Value{}, false
+	}
+
+	holder, ok := fieldValue.Addr().Interface().(secretHolder)
+	if !ok {
+		return reflect.Value{}, false
+	}
+
+	return holder.secretValue(), true
+}
+
+// secretKeys returns every key a secret field can be read from.
+func secretKeys(prefix string, tag envTag) []string {
+	keys := []string{prefix + tag.key}
+
+	for _, alias := range tag.aliases {
+		keys = append(keys, prefix+alias)
+	}
+
+	return keys
+}
+
+// certificateKeys returns the keys holding a certificate field's private
+// key, which are secret whether or not the field is: the tlskey variable,
+// or the field's own keys when the key sits in the same PEM.
+func certificateKeys(prefix string, tag envTag, format valueFormat) []string {
+	if format.tlsKeyEnv != "" {
+		return []string{prefix + format.tlsKeyEnv}
+	}
+
+	return secretKeys(prefix, tag)
+}
+
+func (g *gonfiguration) addSecretKeys(keys []string) {
+	g.Lock()
+	defer g.Unlock()
+
+	for _, key := range keys {
+		g.secretKeys[strings.ToUpper(key)] = struct{}{}
+	}
+}
+
+// isSecret matches case-insensitively, so a secret read through
+// WithCaseInsensitiveKeys is masked under any spelling.
+func (g *gonfiguration) isSecret(key string) bool {
+	g.RLock()
+	defer g.RUnlock()
+
+	_, ok := g.secretKeys[strings.ToUpper(key)]
+
+	return ok
+}
+
+// redactedError stands in for an error about a secret value, whose
+// message could quote the value. errors.Is and errors.As still see the
+// original error.
+type redactedError struct {
+	key string
+	err error
+}
+
+func (e *redactedError) Error() string {
+	return fmt.Sprintf("field %s: invalid value %s", e.key, redacted)
+}
+
+func (e *redactedError) Unwrap() error {
+	return e.err
+}
@@ -0,0 +1,120 @@
+package gonfiguration_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/netip"
+	"testing"
+
+	"github.com/psyb0t/gonfiguration"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSecrets(t *testing.T) {
+	type Config struct {
+		User     string                       `env:"DB_USER"`
+		Password string                       `env:"DB_PASS,secret,alias=DB_PASSWORD"`
+		Token    gonfiguration.Secret[string] `env:"API_TOKEN,required"`
+		PIN      gonfiguration.Secret[int]    `env:"PIN" default:"1234"`
+	}
+
+	t.Run("values parsed and reachable", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		t.Setenv("DB_USER", "app")
+		t.Setenv("DB_PASS", "hunter2")
+		t.Setenv("API_TOKEN", "t0k3n")
+
+		cfg := Config{}
+		require.NoError(t, gonfiguration.Parse(&cfg))
+		require.Equal(t, "hunter2", cfg.Password)
+		require.Equal(t, "t0k3n", cfg.Token.Value())
+		require.Equal(t, 1234, cfg.PIN.Value())
+	})
+
+	t.Run("masked in dumps", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		t.Setenv("DB_USER", "app")
+		t.Setenv("DB_PASSWORD", "hunter2")
+		t.Setenv("API_TOKEN", "t0k3n")
+
+		gonfiguration.SetDefault("PIN", 4321)
+
+		cfg := Config{}
+		require.NoError(t, gonfiguration.Parse(&cfg))
+
+		envVars := gonfiguration.GetEnvVars()
+		require.Equal(t, "app", envVars["DB_USER"])
+		require.Equal(t, "[REDACTED]", envVars["DB_PASSWORD"])
+		require.Equal(t, "[REDACTED]", envVars["API_TOKEN"])
+
+		allValues := gonfiguration.GetAllValues()
+		require.Equal(t, "[REDACTED]", allValues["DB_PASSWORD"])
+		require.Equal(t, "[REDACTED]", allValues["PIN"])
+		require.NotContains(t, fmt.Sprint(allValues), "hunter2")
+	})
+
+	t.Run("masked when printed", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		t.Setenv("API_TOKEN", "t0k3n")
+
+		cfg := Config{}
+		require.NoError(t, gonfiguration.Parse(&cfg))
+
+		for _, format := range []string{"%v", "%+v", "%#v", "%s"} {
+			require.NotContains(t, fmt.Sprintf(format, cfg), "t0k3n", format)
+		}
+
+		require.NotContains(t, fmt.Sprintf("%d", cfg.PIN), "1234")
+
+		out, err := json.Marshal(cfg)
+		require.NoError(t, err)
+		require.Contains(t, string(out), `"Token":"[REDACTED]"`)
+
+		var logs bytes.Buffer
+
+		slog.New(slog.NewTextHandler(&logs, nil)).Info("config", "token", cfg.Token)
+		require.Contains(t, logs.String(), "token=[REDACTED]")
+	})
+
+	t.Run("masked in errors", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		t.Setenv("API_TOKEN", "t0k3n")
+		t.Setenv("PIN", "not-a-number-9876")
+
+		cfg := Config{}
+		err := gonfiguration.Parse(&cfg)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "field PIN: invalid value [REDACTED]")
+		require.NotContains(t, err.Error(), "9876")
+	})
+
+	t.Run("sentinels still match", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		type AddrConfig struct {
+			Upstream gonfiguration.Secret[netip.Addr] `env:"UPSTREAM"`
+		}
+
+		t.Setenv("UPSTREAM", "secret-host")
+
+		cfg := AddrConfig{}
+		err := gonfiguration.Parse(&cfg)
+		require.ErrorIs(t, err, gonfiguration.ErrInvalidAddress)
+		require.NotContains(t, err.Error(), "secret-host")
+	})
+
+	t.Run("required error names the key", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		cfg := Config{}
+		err := gonfiguration.Parse(&cfg)
+		require.ErrorIs(t, err, gonfiguration.ErrRequiredFieldNotSet)
+		require.Contains(t, err.Error(), "field API_TOKEN")
+	})
+}
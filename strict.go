@@ -0,0 +1,155 @@
+package gonfiguration
+
+import (
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/psyb0t/ctxerrors"
+)
+
+// maxSuggestionDistance caps how many edits away a key can be from the
+// one it's suggested for. Shorter keys get proportionally less slack.
+const maxSuggestionDistance = 3
+
+// checkUnknownKeys fails in strict mode with one ErrUnknownKey per env var
+// under the strict prefix that no field looked up.
+func (p *parser) checkUnknownKeys() error {
+	if !p.options.strict {
+		return nil
+	}
+
+	errs := []error{}
+	for _, issue := range p.unknownKeys() {
+		errs = append(errs, issue.Err)
+	}
+
+	return ctxerrors.Join(errs...)
+}
+
+// unknownKeys returns an ErrUnknownKey issue per env var under the strict
+// prefix that no field looked up, with a suggestion if one is close.
+func (p *parser) unknownKeys() []Issue {
+	prefix := p.envKey(p.options.strictPrefix)
+	issues := []Issue{}
+
+	for _, key := range slices.Sorted(maps.Keys(p.envVars)) {
+		if _, ok := p.known[key]; ok || !strings.HasPrefix(key, prefix) {
+			continue
+		}
+
+		if near, ok := suggestKey(key, maps.Keys(p.known)); ok {
+			issues = append(issues, Issue{
+				Key: key,
+				Err: ctxerrors.Wrapf(ErrUnknownKey, "%s (did you mean %s?)", key, near),
+			})
+
+			continue
+		}
+
+		issues = append(issues, Issue{Key: key, Err: ctxerrors.Wrap(ErrUnknownKey, key)})
+	}
+
+	return issues
+}
+
+// requiredFieldError reports a missing required key, pointing at a set
+// env var no field of the destination reads if its name is close enough
+// to be a typo.
+func (p *parser) requiredFieldError(key string) error {
+	if p.probing {
+		return ctxerrors.Wrapf(ErrRequiredFieldNotSet, "field %s", key)
+	}
+
+	readKeys := p.destinationKeys()
+	unread := func(yield func(string) bool) {
+		for envKey := range p.envVars {
+			if _, ok := readKeys[envKey]; !ok && !yield(envKey) {
+				return
+			}
+		}
+	}
+
+	if near, ok := suggestKey(p.envKey(key), unread); ok {
+		return ctxerrors.Wrapf(ErrRequiredFieldNotSet, "field %s (did you mean %s?)", key, near)
+	}
+
+	return ctxerrors.Wrapf(ErrRequiredFieldNotSet, "field %s", key)
+}
+
+// destinationKeys returns every key the destination's fields look up,
+// including fields after the one being parsed. They come from walking a
+// throw-away value of the destination's type the way Check does, once per
+// parse and only when a required key is missing.
+func (p *parser) destinationKeys() map[string]struct{} {
+	if p.readKeys != nil {
+		return p.readKeys
+	}
+
+	if p.root == nil {
+		return p.known
+	}
+
+	probe := &parser{
+		envVars:       p.envVars,
+		options:       p.options,
+		foldConflicts: p.foldConflicts,
+		known:         map[string]struct{}{},
+		check:         &CheckReport{},
+		probing:       true,
+	}
+	probe.options.report = nil
+
+	// Only the keys looked up matter; problems are the real parse's to report
+	_ = probe.parseFields(reflect.New(p.root).Elem(), p.options.prefix, "")
+	p.readKeys = probe.known
+
+	return p.readKeys
+}
+
+// suggestKey returns the candidate closest to key by edit distance, ties
+// going to the alphabetically first, if any is close enough.
+func suggestKey(key string, candidates func(yield func(string) bool)) (string, bool) {
+	limit := min(maxSuggestionDistance, len(key)/3)
+	best, bestDistance := "", limit+1
+
+	for _, candidate := range slices.Sorted(candidates) {
+		if candidate == key {
+			continue
+		}
+
+		if d := editDistance(key, candidate); d < bestDistance {
+			best, bestDistance = candidate, d
+		}
+	}
+
+	return best, best != ""
+}
+
+// editDistance is the Levenshtein distance between a and b in bytes.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+
+		prev, curr = curr, prev
+	}
+
+	return prev[len(b)]
+}
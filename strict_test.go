@@ -0,0 +1,118 @@
+package gonfiguration_test
+
+import (
+	"testing"
+
+	"github.com/psyb0t/gonfiguration"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseStrict(t *testing.T) {
+	type Upstream struct {
+		Host string `env:"HOST"`
+	}
+
+	type Config struct {
+		DBPassword string     `env:"MYAPP_DB_PASSWORD"`
+		Port       int        `env:"MYAPP_PORT" default:"80"`
+		Upstreams  []Upstream `env:"MYAPP_UPSTREAM"`
+	}
+
+	parse := func(cfg *Config) error {
+		return gonfiguration.Parse(cfg, gonfiguration.WithStrict("MYAPP_"))
+	}
+
+	t.Run("only known keys", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		t.Setenv("MYAPP_DB_PASSWORD", "hunter2")
+		t.Setenv("MYAPP_UPSTREAM_0_HOST", "a.internal")
+		t.Setenv("OTHERAPP_THING", "not ours")
+
+		cfg := Config{}
+		require.NoError(t, parse(&cfg))
+	})
+
+	t.Run("typo gets a suggestion", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		t.Setenv("MYAPP_DB_PSSWORD", "hunter2")
+
+		cfg := Config{}
+		err := parse(&cfg)
+		require.ErrorIs(t, err, gonfiguration.ErrUnknownKey)
+		require.Contains(t, err.Error(), "MYAPP_DB_PSSWORD (did you mean MYAPP_DB_PASSWORD?)")
+	})
+
+	t.Run("every unknown key reported", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		t.Setenv("MYAPP_PROT", "8080")
+		t.Setenv("MYAPP_COMPLETELY_DIFFERENT", "x")
+		t.Setenv("MYAPP_UPSTREAM_0_HOTS", "a.internal")
+
+		cfg := Config{}
+		err := parse(&cfg)
+		require.ErrorIs(t, err, gonfiguration.ErrUnknownKey)
+		require.Contains(t, err.Error(), "MYAPP_PROT (did you mean MYAPP_PORT?)")
+		require.Contains(t, err.Error(), "MYAPP_COMPLETELY_DIFFERENT")
+		require.NotContains(t, err.Error(), "MYAPP_COMPLETELY_DIFFERENT (did you mean")
+		require.Contains(t, err.Error(), "MYAPP_UPSTREAM_0_HOTS")
+	})
+
+	t.Run("off by default", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		t.Setenv("MYAPP_DB_PSSWORD", "hunter2")
+
+		cfg := Config{}
+		require.NoError(t, gonfiguration.Parse(&cfg))
+	})
+}
+
+func TestParseRequiredSuggestion(t *testing.T) {
+	type Config struct {
+		DBPassword string `env:"MYAPP_DB_PASSWORD,required"`
+	}
+
+	t.Run("near miss suggested", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		t.Setenv("MYAPP_DB_PSSWORD", "hunter2")
+
+		cfg := Config{}
+		err := gonfiguration.Parse(&cfg)
+		require.ErrorIs(t, err, gonfiguration.ErrRequiredFieldNotSet)
+		require.Contains(t, err.Error(), "field MYAPP_DB_PASSWORD (did you mean MYAPP_DB_PSSWORD?)")
+	})
+
+	t.Run("keys read by later fields not suggested", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		type LaterConfig struct {
+			DBPassword  string `env:"MYAPP_DB_PASSWORD,required"`
+			DBPassword2 string `env:"MYAPP_DB_PASSWORD2"`
+		}
+
+		t.Setenv("MYAPP_DB_PASSWORD2", "hunter2")
+
+		cfg := LaterConfig{}
+		err := gonfiguration.Parse(&cfg)
+		require.ErrorIs(t, err, gonfiguration.ErrRequiredFieldNotSet)
+		require.NotContains(t, err.Error(), "did you mean")
+
+		report, err := gonfiguration.Check(LaterConfig{})
+		require.NoError(t, err)
+		require.Len(t, report.Missing, 1)
+		require.NotContains(t, report.Missing[0].Err.Error(), "did you mean")
+	})
+
+	t.Run("no near miss", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		cfg := Config{}
+		err := gonfiguration.Parse(&cfg)
+		require.ErrorIs(t, err, gonfiguration.ErrRequiredFieldNotSet)
+		require.NotContains(t, err.Error(), "did you mean")
+	})
+}
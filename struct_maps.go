@@ -0,0 +1,124 @@
+package gonfiguration
+
+import (
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/psyb0t/ctxerrors"
+)
+
+// isNamedStructMap reports whether typ is a string-keyed map of plain
+// structs, which is filled from keys carrying the map key as a segment.
+func isNamedStructMap(typ reflect.Type) bool {
+	if typ.Kind() != reflect.Map || typ.Key().Kind() != reflect.String {
+		return false
+	}
+
+	return isNestedStruct(typ.Elem())
+}
+
+// fillNamedStructMap fills a map[string]Struct field from keys like
+// KEY_<NAME>_DSN, one element per NAME found in the env snapshot. Map keys are
+// lowercased unless the tag has the keepcase option.
+func (p *parser) fillNamedStructMap(
+	fieldValue reflect.Value,
+	key, path string,
+	tag envTag,
+) error {
+	typ := fieldValue.Type()
+	p.ownedPrefixes = append(p.ownedPrefixes, p.envKey(key+indexSeparator))
+	names := discoverNames(p.envVars, p.envKey(key+indexSeparator), p.elemFieldKeys(typ.Elem()))
+
+	if len(names) == 0 {
+		return p.setDefaultOrRequire(fieldValue, key, path, tag)
+	}
+
+	rawNames := map[string]string{}
+
+	for _, name := range names {
+		mapKey := name
+		if !tag.keepCase {
+			mapKey = strings.ToLower(name)
+		}
+
+		if other, ok := rawNames[mapKey]; ok {
+			return ctxerrors.Wrapf(
+				ErrDuplicateMapKey,
+				"field %s: %s and %s both map to %q", key, other, name, mapKey,
+			)
+		}
+
+		rawNames[mapKey] = name
+	}
+
+	result := reflect.MakeMapWithSize(typ, len(rawNames))
+
+	for _, mapKey := range slices.Sorted(maps.Keys(rawNames)) {
+		elem := reflect.New(typ.Elem()).Elem()
+		elemPrefix := key + indexSeparator + rawNames[mapKey] + indexSeparator
+		elemPath := path + "[" + strconv.Quote(mapKey) + "]."
+
+		if err := p.parseFields(elem, elemPrefix, elemPath); err != nil {
+			return ctxerrors.Wrapf(err, "field %s name %s", key, rawNames[mapKey])
+		}
+
+		result.SetMapIndex(reflect.ValueOf(mapKey).Convert(typ.Key()), elem)
+	}
+
+	fieldValue.Set(result)
+
+	return nil
+}
+
+// discoverNames returns the sorted distinct NAME of every PREFIX<NAME>_KEY
+// env var where KEY is one of fieldKeys. Names may contain the separator
+// themselves, so the longest matching field key wins.
+func discoverNames(
+	envVars map[string]string,
+	prefix string,
+	fieldKeys []string,
+) []string {
+	seen := map[string]struct{}{}
+
+	for envKey := range envVars {
+		rest, ok := strings.CutPrefix(envKey, prefix)
+		if !ok {
+			continue
+		}
+
+		for _, fieldKey := range fieldKeys {
+			name, ok := strings.CutSuffix(rest, indexSeparator+fieldKey)
+			if ok && name != "" {
+				seen[name] = struct{}{}
+
+				break
+			}
+		}
+	}
+
+	return slices.Sorted(maps.Keys(seen))
+}
+
+// elemFieldKeys returns the keys of the tagged fields of a map element
+// struct, longest first.
+func (p *parser) elemFieldKeys(typ reflect.Type) []string {
+	keys := []string{}
+
+	for i := range typ.NumField() {
+		tag, ok := p.fieldTag(typ.Field(i))
+		if !ok || tag.key == "" {
+			continue
+		}
+
+		keys = append(keys, p.envKey(tag.key))
+	}
+
+	slices.SortFunc(keys, func(a, b string) int {
+		return len(b) - len(a)
+	})
+
+	return keys
+}
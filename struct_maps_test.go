@@ -0,0 +1,134 @@
+package gonfiguration_test
+
+import (
+	"testing"
+
+	"github.com/psyb0t/gonfiguration"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseNamedStructMap(t *testing.T) {
+	type TenantConfig struct {
+		DSN       string `env:"DSN,required"`
+		Quota     int    `env:"QUOTA" default:"100"`
+		QuotaHard int    `env:"QUOTA_HARD"`
+	}
+
+	type Config struct {
+		Tenants map[string]TenantConfig `env:"TENANT"`
+	}
+
+	t.Run("names discovered and lowercased", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		t.Setenv("TENANT_ACME_DSN", "postgres://acme")
+		t.Setenv("TENANT_ACME_QUOTA", "500")
+		t.Setenv("TENANT_GLOBEX_DSN", "postgres://globex")
+
+		cfg := Config{}
+		require.NoError(t, gonfiguration.Parse(&cfg))
+		require.Equal(t, map[string]TenantConfig{
+			"acme":   {DSN: "postgres://acme", Quota: 500},
+			"globex": {DSN: "postgres://globex", Quota: 100},
+		}, cfg.Tenants)
+	})
+
+	t.Run("names containing the separator", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		t.Setenv("TENANT_ACME_CORP_DSN", "postgres://acme")
+		t.Setenv("TENANT_ACME_CORP_QUOTA_HARD", "900")
+
+		cfg := Config{}
+		require.NoError(t, gonfiguration.Parse(&cfg))
+		require.Equal(t, map[string]TenantConfig{
+			"acme_corp": {DSN: "postgres://acme", Quota: 100, QuotaHard: 900},
+		}, cfg.Tenants)
+	})
+
+	t.Run("keepcase option", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		type KeepCaseConfig struct {
+			Tenants map[string]TenantConfig `env:"TENANT,keepcase"`
+		}
+
+		t.Setenv("TENANT_Acme_DSN", "postgres://acme")
+
+		cfg := KeepCaseConfig{}
+		require.NoError(t, gonfiguration.Parse(&cfg))
+		require.Contains(t, cfg.Tenants, "Acme")
+	})
+
+	t.Run("names colliding after lowercasing", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		t.Setenv("TENANT_ACME_DSN", "postgres://a")
+		t.Setenv("TENANT_acme_DSN", "postgres://b")
+
+		cfg := Config{}
+		require.ErrorIs(t, gonfiguration.Parse(&cfg), gonfiguration.ErrDuplicateMapKey)
+	})
+
+	t.Run("element required field", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		t.Setenv("TENANT_ACME_QUOTA", "5")
+
+		cfg := Config{}
+		err := gonfiguration.Parse(&cfg)
+		require.ErrorIs(t, err, gonfiguration.ErrRequiredFieldNotSet)
+		require.Contains(t, err.Error(), "field TENANT_ACME_DSN")
+	})
+
+	t.Run("unrelated keys ignored", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		t.Setenv("TENANT_ACME_DSN", "postgres://acme")
+		t.Setenv("TENANT_ACME_COLOR", "blue")
+		t.Setenv("TENANT_DSN", "nameless")
+		t.Setenv("TENANTS_X_DSN", "wrong prefix")
+
+		cfg := Config{}
+		require.NoError(t, gonfiguration.Parse(&cfg))
+		require.Len(t, cfg.Tenants, 1)
+		require.Contains(t, cfg.Tenants, "acme")
+	})
+
+	t.Run("named key type", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		type TenantID string
+
+		type NamedKeyConfig struct {
+			Tenants map[TenantID]TenantConfig `env:"TENANT"`
+		}
+
+		t.Setenv("TENANT_ACME_DSN", "postgres://acme")
+
+		cfg := NamedKeyConfig{}
+		require.NoError(t, gonfiguration.Parse(&cfg))
+		require.Contains(t, cfg.Tenants, TenantID("acme"))
+	})
+
+	t.Run("none present falls back to programmatic default", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		gonfiguration.SetDefault("TENANT", map[string]TenantConfig{"default": {DSN: "sqlite://"}})
+
+		cfg := Config{}
+		require.NoError(t, gonfiguration.Parse(&cfg))
+		require.Equal(t, "sqlite://", cfg.Tenants["default"].DSN)
+	})
+
+	t.Run("none present but required", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		type RequiredConfig struct {
+			Tenants map[string]TenantConfig `env:"TENANT,required"`
+		}
+
+		cfg := RequiredConfig{}
+		require.ErrorIs(t, gonfiguration.Parse(&cfg), gonfiguration.ErrRequiredFieldNotSet)
+	})
+}
@@ -0,0 +1,342 @@
+package gonfiguration
+
+import (
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/psyb0t/ctxerrors"
+)
+
+const (
+	// durationFormatExtended is the `duration` tag value that opts a
+	// time.Duration field into day/week units and ISO-8601 durations.
+	durationFormatExtended = "extended"
+
+	// layoutUnix and layoutUnixMilli are `layout` tag values for
+	// time.Time fields holding Unix timestamps instead of formatted dates.
+	layoutUnix      = "unix"
+	layoutUnixMilli = "unixmilli"
+
+	day  = 24 * time.Hour
+	week = 7 * day
+
+	isoDurationPrefix = "P"
+	isoTimeSeparator  = "T"
+
+	// isoDateDesignators and isoTimeDesignators list the ISO-8601 units
+	// in the order they have to appear in, each at most once.
+	isoDateDesignators = "WD"
+	isoTimeDesignators = "HMS"
+)
+
+func setDuration(
+	fieldValue reflect.Value,
+	envVal string,
+	format valueFormat,
+) error {
+	parse := time.ParseDuration
+	if format.extendedDuration {
+		parse = parseExtendedDuration
+	}
+
+	d, err := parse(envVal)
+	if err != nil {
+		return ctxerrors.Wrap(err, "failed to parse duration")
+	}
+
+	fieldValue.Set(reflect.ValueOf(d))
+
+	return nil
+}
+
+func setTime(
+	fieldValue reflect.Value,
+	envVal string,
+	format valueFormat,
+) error {
+	t, err := parseTime(envVal, format.layout)
+	if err != nil {
+		return ctxerrors.Wrap(err, "failed to parse time")
+	}
+
+	fieldValue.Set(reflect.ValueOf(t))
+
+	return nil
+}
+
+func setLocation(
+	fieldValue reflect.Value,
+	envVal string,
+	_ valueFormat,
+) error {
+	loc, err := time.LoadLocation(envVal)
+	if err != nil {
+		return ctxerrors.Wrap(err, "failed to load location")
+	}
+
+	fieldValue.Set(reflect.ValueOf(loc))
+
+	return nil
+}
+
+func formatTime(v reflect.Value, format valueFormat) (string, error) {
+	t, _ := v.Interface().(time.Time)
+
+	switch format.layout {
+	case layoutUnix:
+		return strconv.FormatInt(t.Unix(), 10), nil
+	case layoutUnixMilli:
+		return strconv.FormatInt(t.UnixMilli(), 10), nil
+	case "":
+		return t.Format(time.RFC3339Nano), nil
+	default:
+		return t.Format(format.layout), nil
+	}
+}
+
+func parseTime(val, layout string) (time.Time, error) {
+	if layout == layoutUnix || layout == layoutUnixMilli {
+		return parseUnixTime(val, layout)
+	}
+
+	if layout == "" {
+		layout = time.RFC3339
+	}
+
+	t, err := time.Parse(layout, val)
+	if err != nil {
+		return time.Time{}, ctxerrors.Wrapf(err, "layout %q", layout)
+	}
+
+	return t, nil
+}
+
+func parseUnixTime(val, layout string) (time.Time, error) {
+	num, err := strconv.ParseInt(val, 10, 64)
+	if err != nil {
+		return time.Time{}, ctxerrors.Wrap(err, "failed to parse unix timestamp")
+	}
+
+	if layout == layoutUnixMilli {
+		return time.UnixMilli(num).UTC(), nil
+	}
+
+	return time.Unix(num, 0).UTC(), nil
+}
+
+// parseExtendedDuration accepts everything time.ParseDuration does plus
+// "d" (24h) and "w" (7d) units, e.g. "1w2d12h", and ISO-8601 durations
+// such as "P1DT2H" or "PT30M". Years and months are rejected since they
+// have no fixed length.
+func parseExtendedDuration(val string) (time.Duration, error) {
+	rest := strings.TrimSpace(val)
+
+	sign := time.Duration(1)
+
+	switch {
+	case strings.HasPrefix(rest, "-"):
+		sign = -1
+		rest = rest[1:]
+	case strings.HasPrefix(rest, "+"):
+		rest = rest[1:]
+	}
+
+	parse := parseUnitDuration
+	if strings.HasPrefix(strings.ToUpper(rest), isoDurationPrefix) {
+		parse = parseISODuration
+	}
+
+	d, err := parse(rest)
+	if err != nil {
+		return 0, ctxerrors.Wrapf(err, "duration %q", val)
+	}
+
+	return sign * d, nil
+}
+
+func parseUnitDuration(val string) (time.Duration, error) {
+	if val == "" {
+		return 0, ErrInvalidDuration
+	}
+
+	var total time.Duration
+
+	for rest := val; rest != ""; {
+		numEnd := strings.IndexFunc(rest, isNotDurationNumber)
+		if numEnd == 0 {
+			return 0, ctxerrors.Wrapf(ErrInvalidDuration, "expected number at %q", rest)
+		}
+
+		if numEnd < 0 {
+			numEnd = len(rest)
+		}
+
+		unitEnd := numEnd + strings.IndexFunc(rest[numEnd:], isDurationNumber)
+		if unitEnd < numEnd {
+			unitEnd = len(rest)
+		}
+
+		d, err := unitDuration(rest[:numEnd], rest[numEnd:unitEnd])
+		if err != nil {
+			return 0, err
+		}
+
+		if total, err = addDuration(total, d); err != nil {
+			return 0, err
+		}
+
+		rest = rest[unitEnd:]
+	}
+
+	return total, nil
+}
+
+func unitDuration(num, unit string) (time.Duration, error) {
+	switch unit {
+	case "d":
+		return scaleDuration(num, day)
+	case "w":
+		return scaleDuration(num, week)
+	default:
+		d, err := time.ParseDuration(num + unit)
+		if err != nil {
+			return 0, ctxerrors.Wrap(err, "failed to parse duration component")
+		}
+
+		return d, nil
+	}
+}
+
+func parseISODuration(val string) (time.Duration, error) {
+	body := strings.ToUpper(val)[len(isoDurationPrefix):]
+
+	datePart, timePart, hasTime := strings.Cut(body, isoTimeSeparator)
+	if body == "" || hasTime && timePart == "" {
+		return 0, ctxerrors.Wrapf(ErrInvalidDuration, "empty ISO-8601 duration %q", val)
+	}
+
+	dateTotal, err := sumISOComponents(datePart, false)
+	if err != nil {
+		return 0, err
+	}
+
+	timeTotal, err := sumISOComponents(timePart, true)
+	if err != nil {
+		return 0, err
+	}
+
+	return addDuration(dateTotal, timeTotal)
+}
+
+func sumISOComponents(part string, inTime bool) (time.Duration, error) {
+	var total time.Duration
+
+	designators := isoDateDesignators
+	if inTime {
+		designators = isoTimeDesignators
+	}
+
+	last := -1
+
+	for rest := part; rest != ""; {
+		numEnd := strings.IndexFunc(rest, isNotISONumber)
+		if numEnd <= 0 {
+			return 0, ctxerrors.Wrapf(ErrInvalidDuration, "expected number at %q", rest)
+		}
+
+		unit, ok := isoUnit(rest[numEnd], inTime)
+		if !ok {
+			return 0, ctxerrors.Wrapf(
+				ErrInvalidDuration,
+				"unsupported ISO-8601 designator %q", rest[numEnd],
+			)
+		}
+
+		pos := strings.IndexByte(designators, rest[numEnd])
+		if pos <= last {
+			return 0, ctxerrors.Wrapf(
+				ErrInvalidDuration,
+				"ISO-8601 designator %q repeated or out of order", rest[numEnd],
+			)
+		}
+
+		last = pos
+
+		d, err := scaleDuration(strings.ReplaceAll(rest[:numEnd], ",", "."), unit)
+		if err != nil {
+			return 0, err
+		}
+
+		if total, err = addDuration(total, d); err != nil {
+			return 0, err
+		}
+
+		rest = rest[numEnd+1:]
+	}
+
+	return total, nil
+}
+
+func isoUnit(designator byte, inTime bool) (time.Duration, bool) {
+	if inTime {
+		switch designator {
+		case 'H':
+			return time.Hour, true
+		case 'M':
+			return time.Minute, true
+		case 'S':
+			return time.Second, true
+		}
+
+		return 0, false
+	}
+
+	switch designator {
+	case 'W':
+		return week, true
+	case 'D':
+		return day, true
+	}
+
+	return 0, false
+}
+
+func scaleDuration(num string, unit time.Duration) (time.Duration, error) {
+	f, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		return 0, ctxerrors.Wrap(err, "failed to parse duration component")
+	}
+
+	// float64(math.MaxInt64) rounds up to 2^63, which is out of range
+	scaled := f * float64(unit)
+	if scaled >= math.MaxInt64 || scaled < math.MinInt64 {
+		return 0, ctxerrors.Wrapf(ErrInvalidDuration, "%s overflows", num)
+	}
+
+	return time.Duration(scaled), nil
+}
+
+// addDuration adds two non-negative durations, failing instead of
+// wrapping around when the sum overflows.
+func addDuration(total, d time.Duration) (time.Duration, error) {
+	if d > math.MaxInt64-total {
+		return 0, ctxerrors.Wrapf(ErrInvalidDuration, "%s + %s overflows", total, d)
+	}
+
+	return total + d, nil
+}
+
+func isDurationNumber(r rune) bool {
+	return r >= '0' && r <= '9' || r == '.'
+}
+
+func isNotDurationNumber(r rune) bool {
+	return !isDurationNumber(r)
+}
+
+func isNotISONumber(r rune) bool {
+	return !isDurationNumber(r) && r != ','
+}
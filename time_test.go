@@ -0,0 +1,215 @@
+package gonfiguration_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/psyb0t/gonfiguration"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTime(t *testing.T) {
+	t.Run("rfc3339 by default", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		type Config struct {
+			ExpiresAt time.Time `env:"EXPIRES_AT"`
+		}
+
+		t.Setenv("EXPIRES_AT", "2026-12-31T23:59:59+02:00")
+
+		cfg := Config{}
+		require.NoError(t, gonfiguration.Parse(&cfg))
+		require.True(t, time.Date(2026, time.December, 31, 21, 59, 59, 0, time.UTC).Equal(cfg.ExpiresAt))
+	})
+
+	t.Run("custom layout", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		type Config struct {
+			Launch time.Time `env:"LAUNCH" layout:"2006-01-02"`
+		}
+
+		t.Setenv("LAUNCH", "2026-03-01")
+
+		cfg := Config{}
+		require.NoError(t, gonfiguration.Parse(&cfg))
+		require.Equal(t, time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC), cfg.Launch)
+	})
+
+	t.Run("unix timestamps", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		type Config struct {
+			Seconds time.Time `env:"SECONDS" layout:"unix"`
+			Millis  time.Time `env:"MILLIS" layout:"unixmilli"`
+		}
+
+		t.Setenv("SECONDS", "1700000000")
+		t.Setenv("MILLIS", "1700000000123")
+
+		cfg := Config{}
+		require.NoError(t, gonfiguration.Parse(&cfg))
+		require.Equal(t, time.Unix(1700000000, 0).UTC(), cfg.Seconds)
+		require.Equal(t, time.UnixMilli(1700000000123).UTC(), cfg.Millis)
+	})
+
+	t.Run("tag default uses layout", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		type Config struct {
+			Window time.Time `env:"WINDOW" layout:"15:04" default:"03:30"`
+		}
+
+		cfg := Config{}
+		require.NoError(t, gonfiguration.Parse(&cfg))
+		require.Equal(t, 3, cfg.Window.Hour())
+		require.Equal(t, 30, cfg.Window.Minute())
+	})
+
+	t.Run("invalid time", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		type Config struct {
+			ExpiresAt time.Time `env:"EXPIRES_AT"`
+		}
+
+		t.Setenv("EXPIRES_AT", "tomorrow")
+
+		cfg := Config{}
+		require.Error(t, gonfiguration.Parse(&cfg))
+	})
+
+	t.Run("invalid unix timestamp", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		type Config struct {
+			ExpiresAt time.Time `env:"EXPIRES_AT" layout:"unix"`
+		}
+
+		t.Setenv("EXPIRES_AT", "2026-01-01")
+
+		cfg := Config{}
+		require.Error(t, gonfiguration.Parse(&cfg))
+	})
+}
+
+func TestParseLocation(t *testing.T) {
+	t.Run("valid location", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		type Config struct {
+			TZ *time.Location `env:"TZ"`
+		}
+
+		t.Setenv("TZ", "Europe/Bucharest")
+
+		cfg := Config{}
+		require.NoError(t, gonfiguration.Parse(&cfg))
+		require.Equal(t, "Europe/Bucharest", cfg.TZ.String())
+	})
+
+	t.Run("default location", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		type Config struct {
+			TZ *time.Location `env:"APP_TZ" default:"UTC"`
+		}
+
+		cfg := Config{}
+		require.NoError(t, gonfiguration.Parse(&cfg))
+		require.Equal(t, time.UTC, cfg.TZ)
+	})
+
+	t.Run("invalid location", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		type Config struct {
+			TZ *time.Location `env:"APP_TZ"`
+		}
+
+		t.Setenv("APP_TZ", "Mars/Olympus_Mons")
+
+		cfg := Config{}
+		require.Error(t, gonfiguration.Parse(&cfg))
+	})
+}
+
+func TestParseExtendedDuration(t *testing.T) {
+	type Config struct {
+		Retention time.Duration `env:"RETENTION" duration:"extended"`
+	}
+
+	testCases := []struct {
+		name        string
+		value       string
+		expected    time.Duration
+		expectError bool
+		errorIs     error
+	}{
+		{name: "go format", value: "1h30m", expected: 90 * time.Minute},
+		{name: "days", value: "7d", expected: 7 * 24 * time.Hour},
+		{name: "weeks", value: "2w", expected: 14 * 24 * time.Hour},
+		{name: "mixed units", value: "1w2d12h", expected: 9*24*time.Hour + 12*time.Hour},
+		{name: "fractional days", value: "1.5d", expected: 36 * time.Hour},
+		{name: "negative", value: "-1d", expected: -24 * time.Hour},
+		{name: "zero", value: "0", expected: 0},
+		{name: "iso days and hours", value: "P1DT2H", expected: 26 * time.Hour},
+		{name: "iso time only", value: "PT30M", expected: 30 * time.Minute},
+		{name: "iso weeks", value: "P2W", expected: 14 * 24 * time.Hour},
+		{name: "iso fractional seconds", value: "PT1,5S", expected: 1500 * time.Millisecond},
+		{name: "iso lowercase", value: "p1dt1m", expected: 24*time.Hour + time.Minute},
+		{name: "iso years rejected", value: "P1Y", errorIs: gonfiguration.ErrInvalidDuration},
+		{name: "iso months rejected", value: "P1M", errorIs: gonfiguration.ErrInvalidDuration},
+		{name: "iso empty", value: "P", errorIs: gonfiguration.ErrInvalidDuration},
+		{name: "iso empty time part", value: "P1DT", errorIs: gonfiguration.ErrInvalidDuration},
+		{name: "sum overflows", value: "15000w15000w", errorIs: gonfiguration.ErrInvalidDuration},
+		{name: "component rounds to 2^63", value: "106751.99116730064591d", errorIs: gonfiguration.ErrInvalidDuration},
+		{name: "iso sum overflows", value: "P15000W15000D", errorIs: gonfiguration.ErrInvalidDuration},
+		{name: "iso repeated designator", value: "P1D1D", errorIs: gonfiguration.ErrInvalidDuration},
+		{name: "iso designators out of order", value: "PT1S1H", errorIs: gonfiguration.ErrInvalidDuration},
+		{name: "iso days before weeks", value: "P1D1W", errorIs: gonfiguration.ErrInvalidDuration},
+		{name: "missing unit", value: "5", expectError: true},
+		{name: "unknown unit", value: "5y", expectError: true},
+		{name: "empty", value: "", errorIs: gonfiguration.ErrInvalidDuration},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			defer gonfiguration.Reset()
+
+			t.Setenv("RETENTION", tc.value)
+
+			cfg := Config{}
+			err := gonfiguration.Parse(&cfg)
+
+			if tc.errorIs != nil {
+				require.ErrorIs(t, err, tc.errorIs)
+
+				return
+			}
+
+			if tc.expectError {
+				require.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, cfg.Retention)
+		})
+	}
+
+	t.Run("not enabled without tag", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		type PlainConfig struct {
+			Retention time.Duration `env:"RETENTION"`
+		}
+
+		t.Setenv("RETENTION", "7d")
+
+		cfg := PlainConfig{}
+		require.Error(t, gonfiguration.Parse(&cfg))
+	})
+}
@@ -0,0 +1,181 @@
+package gonfiguration
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/psyb0t/ctxerrors"
+)
+
+const (
+	pemBeginMarker     = "-----BEGIN"
+	pemCertificateType = "CERTIFICATE"
+	pemPrivateKeyType  = "PRIVATE KEY"
+)
+
+// setCertificate builds a tls.Certificate from the certificate material in
+// envVal and the private key named by the field's `tlskey` tag. Without a
+// `tlskey` tag the key is expected in the same material as the certificate.
+func setCertificate(
+	fieldValue reflect.Value,
+	envVal string,
+	format valueFormat,
+) error {
+	certPEM, err := loadPEM(envVal)
+	if err != nil {
+		return ctxerrors.Wrap(err, "failed to load certificate")
+	}
+
+	keyPEM := certPEM
+
+	if format.tlsKeyEnv != "" {
+		if !format.hasTLSKey {
+			return ctxerrors.Wrapf(ErrInvalidCertificate, "private key %s not set", format.tlsKeyEnv)
+		}
+
+		keyPEM, err = loadPEM(format.tlsKey)
+		if err != nil {
+			return ctxerrors.Wrapf(err, "failed to load private key from %s", format.tlsKeyEnv)
+		}
+	}
+
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return ctxerrors.Wrapf(ErrInvalidCertificate, "certificate and private key: %v", err)
+	}
+
+	for _, der := range cert.Certificate {
+		if _, err := parseValidCertificate(der); err != nil {
+			return err
+		}
+	}
+
+	fieldValue.Set(reflect.ValueOf(cert))
+
+	return nil
+}
+
+func setCertPool(
+	fieldValue reflect.Value,
+	envVal string,
+	_ valueFormat,
+) error {
+	material, err := loadPEM(envVal)
+	if err != nil {
+		return ctxerrors.Wrap(err, "failed to load CA certificates")
+	}
+
+	pool := x509.NewCertPool()
+	count := 0
+
+	for block, rest := pem.Decode(material); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type != pemCertificateType {
+			continue
+		}
+
+		cert, err := parseValidCertificate(block.Bytes)
+		if err != nil {
+			return ctxerrors.Wrapf(err, "CA certificate %d", count)
+		}
+
+		pool.AddCert(cert)
+
+		count++
+	}
+
+	if count == 0 {
+		return ctxerrors.Wrap(ErrInvalidCertificate, "no PEM certificates found")
+	}
+
+	fieldValue.Set(reflect.ValueOf(pool))
+
+	return nil
+}
+
+// parseValidCertificate parses a DER certificate and rejects it unless
+// the current time falls within its validity period.
+func parseValidCertificate(der []byte) (*x509.Certificate, error) {
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, ctxerrors.Wrapf(ErrInvalidCertificate, "%v", err)
+	}
+
+	now := time.Now()
+
+	if now.After(cert.NotAfter) {
+		return nil, ctxerrors.Wrapf(
+			ErrCertificateExpired,
+			"%q expired at %s", cert.Subject.CommonName, cert.NotAfter.Format(time.RFC3339),
+		)
+	}
+
+	if now.Before(cert.NotBefore) {
+		return nil, ctxerrors.Wrapf(
+			ErrCertificateNotYetValid,
+			"%q valid from %s", cert.Subject.CommonName, cert.NotBefore.Format(time.RFC3339),
+		)
+	}
+
+	return cert, nil
+}
+
+// loadPEM returns val itself when it holds inline PEM, with literal "\n"
+// sequences expanded since single-line env vars often carry them, and
+// otherwise treats val as a path to read the PEM from.
+func loadPEM(val string) ([]byte, error) {
+	if strings.Contains(val, pemBeginMarker) {
+		return []byte(strings.ReplaceAll(val, `\n`, "\n")), nil
+	}
+
+	if val == "" {
+		return nil, ctxerrors.Wrap(ErrInvalidCertificate, "empty PEM material")
+	}
+
+	material, err := os.ReadFile(val) //nolint:gosec
+	if err != nil {
+		return nil, ctxerrors.Wrap(err, "failed to read PEM file")
+	}
+
+	return material, nil
+}
+
+// formatCertificate PEM-encodes the certificate chain, followed by the
+// private key unless the field reads its key from a tlskey env var.
+func formatCertificate(v reflect.Value, format valueFormat) (string, error) {
+	cert, _ := v.Interface().(tls.Certificate)
+
+	var b strings.Builder
+
+	for _, der := range cert.Certificate {
+		_ = pem.Encode(&b, &pem.Block{Type: pemCertificateType, Bytes: der})
+	}
+
+	if format.tlsKeyEnv != "" {
+		return b.String(), nil
+	}
+
+	key, err := formatPrivateKey(cert)
+	if err != nil {
+		return "", err
+	}
+
+	return b.String() + key, nil
+}
+
+func formatPrivateKey(cert tls.Certificate) (string, error) {
+	if cert.PrivateKey == nil {
+		return "", nil
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(cert.PrivateKey)
+	if err != nil {
+		return "", ctxerrors.Wrapf(ErrInvalidCertificate, "failed to encode private key: %v", err)
+	}
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: pemPrivateKeyType, Bytes: der})), nil
+}
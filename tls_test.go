@@ -0,0 +1,304 @@
+package gonfiguration_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/psyb0t/gonfiguration"
+	"github.com/stretchr/testify/require"
+)
+
+func generateTestCert(t *testing.T, commonName string, notBefore, notAfter time.Time) (string, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             notBefore,
+		NotAfter:              notAfter,
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+
+	return string(certPEM), string(keyPEM)
+}
+
+func TestParseTLSCertificate(t *testing.T) {
+	now := time.Now()
+	certPEM, keyPEM := generateTestCert(t, "svc", now.Add(-time.Hour), now.Add(time.Hour))
+
+	type Config struct {
+		Cert tls.Certificate `env:"TLS_CERT" tlskey:"TLS_KEY"`
+	}
+
+	t.Run("inline PEM", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		t.Setenv("TLS_CERT", certPEM)
+		t.Setenv("TLS_KEY", keyPEM)
+
+		cfg := Config{}
+		require.NoError(t, gonfiguration.Parse(&cfg))
+		require.Len(t, cfg.Cert.Certificate, 1)
+		require.Equal(t, "svc", cfg.Cert.Leaf.Subject.CommonName)
+	})
+
+	t.Run("inline PEM with escaped newlines", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		t.Setenv("TLS_CERT", strings.ReplaceAll(certPEM, "\n", `\n`))
+		t.Setenv("TLS_KEY", strings.ReplaceAll(keyPEM, "\n", `\n`))
+
+		cfg := Config{}
+		require.NoError(t, gonfiguration.Parse(&cfg))
+		require.Len(t, cfg.Cert.Certificate, 1)
+	})
+
+	t.Run("file paths", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		dir := t.TempDir()
+		certPath := filepath.Join(dir, "tls.crt")
+		keyPath := filepath.Join(dir, "tls.key")
+		require.NoError(t, os.WriteFile(certPath, []byte(certPEM), 0o600))
+		require.NoError(t, os.WriteFile(keyPath, []byte(keyPEM), 0o600))
+
+		t.Setenv("TLS_CERT", certPath)
+		t.Setenv("TLS_KEY", keyPath)
+
+		cfg := Config{}
+		require.NoError(t, gonfiguration.Parse(&cfg))
+		require.Len(t, cfg.Cert.Certificate, 1)
+	})
+
+	t.Run("combined PEM without tlskey tag", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		type CombinedConfig struct {
+			Cert tls.Certificate `env:"TLS_BUNDLE"`
+		}
+
+		t.Setenv("TLS_BUNDLE", certPEM+keyPEM)
+
+		cfg := CombinedConfig{}
+		require.NoError(t, gonfiguration.Parse(&cfg))
+		require.Len(t, cfg.Cert.Certificate, 1)
+	})
+
+	t.Run("private key is masked", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		type CombinedConfig struct {
+			Cert tls.Certificate `env:"TLS_BUNDLE"`
+		}
+
+		t.Setenv("TLS_CERT", certPEM)
+		t.Setenv("TLS_KEY", keyPEM)
+		t.Setenv("TLS_BUNDLE", certPEM+keyPEM)
+
+		require.NoError(t, gonfiguration.Parse(&Config{}))
+		require.NoError(t, gonfiguration.Parse(&CombinedConfig{}))
+
+		envVars := gonfiguration.GetEnvVars()
+		require.Equal(t, "[REDACTED]", envVars["TLS_KEY"])
+		require.Equal(t, "[REDACTED]", envVars["TLS_BUNDLE"])
+		require.Equal(t, certPEM, envVars["TLS_CERT"])
+		require.Equal(t, "[REDACTED]", gonfiguration.GetAllValues()["TLS_KEY"])
+	})
+
+	t.Run("inline private key kept out of Explain", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		type CombinedConfig struct {
+			Cert tls.Certificate `env:"TLS_BUNDLE"`
+		}
+
+		t.Setenv("TLS_BUNDLE", certPEM+keyPEM)
+
+		report, err := gonfiguration.Explain(&CombinedConfig{})
+		require.NoError(t, err)
+		require.Len(t, report, 1)
+		require.True(t, report[0].Secret)
+		require.Equal(t, "[REDACTED]", report[0].Value)
+		require.NotContains(t, report.String(), "PRIVATE KEY")
+	})
+
+	t.Run("marshal writes the key after the certificate", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		t.Setenv("TLS_CERT", certPEM)
+		t.Setenv("TLS_KEY", keyPEM)
+
+		cfg := Config{}
+		require.NoError(t, gonfiguration.Parse(&cfg))
+
+		environ, err := gonfiguration.MarshalEnviron(cfg)
+		require.NoError(t, err)
+		require.Len(t, environ, 2)
+		require.True(t, strings.HasPrefix(environ[0], "TLS_CERT=-----BEGIN CERTIFICATE-----"))
+		require.Equal(t, "TLS_KEY=[REDACTED]", environ[1])
+
+		environ, err = gonfiguration.MarshalEnviron(cfg, gonfiguration.WithSecretsRevealed())
+		require.NoError(t, err)
+
+		roundTrip := Config{}
+		require.NoError(t, gonfiguration.Parse(&roundTrip, gonfiguration.WithEnviron(environ)))
+		require.Equal(t, cfg.Cert.Certificate, roundTrip.Cert.Certificate)
+	})
+
+	t.Run("mismatched key", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		_, otherKeyPEM := generateTestCert(t, "other", now.Add(-time.Hour), now.Add(time.Hour))
+
+		t.Setenv("TLS_CERT", certPEM)
+		t.Setenv("TLS_KEY", otherKeyPEM)
+
+		cfg := Config{}
+		err := gonfiguration.Parse(&cfg)
+		require.ErrorIs(t, err, gonfiguration.ErrInvalidCertificate)
+		require.Contains(t, err.Error(), "field TLS_CERT")
+	})
+
+	t.Run("missing key env var", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		t.Setenv("TLS_CERT", certPEM)
+
+		cfg := Config{}
+		err := gonfiguration.Parse(&cfg)
+		require.ErrorIs(t, err, gonfiguration.ErrInvalidCertificate)
+		require.Contains(t, err.Error(), "TLS_KEY not set")
+	})
+
+	t.Run("unreadable key file", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		t.Setenv("TLS_CERT", certPEM)
+		t.Setenv("TLS_KEY", filepath.Join(t.TempDir(), "missing.key"))
+
+		cfg := Config{}
+		err := gonfiguration.Parse(&cfg)
+		require.ErrorIs(t, err, os.ErrNotExist)
+		require.Contains(t, err.Error(), "failed to load private key from TLS_KEY")
+	})
+
+	t.Run("expired certificate", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		expiredCert, expiredKey := generateTestCert(t, "old", now.Add(-2*time.Hour), now.Add(-time.Hour))
+
+		t.Setenv("TLS_CERT", expiredCert)
+		t.Setenv("TLS_KEY", expiredKey)
+
+		cfg := Config{}
+		err := gonfiguration.Parse(&cfg)
+		require.ErrorIs(t, err, gonfiguration.ErrCertificateExpired)
+		require.Contains(t, err.Error(), "field TLS_CERT")
+	})
+
+	t.Run("not yet valid certificate", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		futureCert, futureKey := generateTestCert(t, "future", now.Add(time.Hour), now.Add(2*time.Hour))
+
+		t.Setenv("TLS_CERT", futureCert)
+		t.Setenv("TLS_KEY", futureKey)
+
+		cfg := Config{}
+		require.ErrorIs(t, gonfiguration.Parse(&cfg), gonfiguration.ErrCertificateNotYetValid)
+	})
+}
+
+func TestParseCertPool(t *testing.T) {
+	now := time.Now()
+	caOne, _ := generateTestCert(t, "ca-one", now.Add(-time.Hour), now.Add(time.Hour))
+	caTwo, _ := generateTestCert(t, "ca-two", now.Add(-time.Hour), now.Add(time.Hour))
+
+	type Config struct {
+		CA *x509.CertPool `env:"TLS_CA"`
+	}
+
+	t.Run("bundle", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		t.Setenv("TLS_CA", caOne+caTwo)
+
+		cfg := Config{}
+		require.NoError(t, gonfiguration.Parse(&cfg))
+
+		expected := x509.NewCertPool()
+		require.True(t, expected.AppendCertsFromPEM([]byte(caOne+caTwo)))
+		require.True(t, expected.Equal(cfg.CA))
+	})
+
+	t.Run("file path", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		path := filepath.Join(t.TempDir(), "ca.pem")
+		require.NoError(t, os.WriteFile(path, []byte(caOne), 0o600))
+
+		t.Setenv("TLS_CA", path)
+
+		cfg := Config{}
+		require.NoError(t, gonfiguration.Parse(&cfg))
+		require.NotNil(t, cfg.CA)
+	})
+
+	t.Run("no certificates", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		t.Setenv("TLS_CA", "-----BEGIN NOTHING-----\n-----END NOTHING-----\n")
+
+		cfg := Config{}
+		err := gonfiguration.Parse(&cfg)
+		require.ErrorIs(t, err, gonfiguration.ErrInvalidCertificate)
+		require.Contains(t, err.Error(), "field TLS_CA")
+	})
+
+	t.Run("expired CA", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		expired, _ := generateTestCert(t, "old-ca", now.Add(-2*time.Hour), now.Add(-time.Hour))
+
+		t.Setenv("TLS_CA", caOne+expired)
+
+		cfg := Config{}
+		err := gonfiguration.Parse(&cfg)
+		require.ErrorIs(t, err, gonfiguration.ErrCertificateExpired)
+		require.Contains(t, err.Error(), "CA certificate 1")
+	})
+
+	t.Run("empty value", func(t *testing.T) {
+		defer gonfiguration.Reset()
+
+		t.Setenv("TLS_CA", "")
+
+		cfg := Config{}
+		require.ErrorIs(t, gonfiguration.Parse(&cfg), gonfiguration.ErrInvalidCertificate)
+	})
+}